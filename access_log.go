@@ -0,0 +1,211 @@
+package web
+
+import (
+	json "github.com/json-iterator/go"
+	core "github.com/procyon-projects/procyon-core"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	AccessLogFormatJson     = "json"
+	AccessLogFormatCombined = "combined"
+
+	accessLogStartTimeKey = "procyon.web.access-log-start-time"
+	combinedTimeLayout    = "02/Jan/2006:15:04:05 -0700"
+)
+
+type AccessLogEntry struct {
+	Time      time.Time `json:"time"`
+	ContextId string    `json:"contextId"`
+	Method    string    `json:"method"`
+	Route     string    `json:"route"`
+	Path      string    `json:"path"`
+	Query     string    `json:"query,omitempty"`
+	Protocol  string    `json:"protocol"`
+	Status    int       `json:"status"`
+	LatencyMs float64   `json:"latencyMs"`
+	BytesIn   int       `json:"bytesIn"`
+	BytesOut  int       `json:"bytesOut"`
+	ClientIP  string    `json:"clientIp"`
+	UserAgent string    `json:"userAgent,omitempty"`
+	Referer   string    `json:"referer,omitempty"`
+}
+
+func (entry AccessLogEntry) formatCombined() string {
+	requestLine := entry.Method + " " + entry.Path
+	if entry.Query != "" {
+		requestLine += "?" + entry.Query
+	}
+	requestLine += " " + entry.Protocol
+
+	bytesOut := "-"
+	if entry.BytesOut > 0 {
+		bytesOut = strconv.Itoa(entry.BytesOut)
+	}
+
+	return valueOrDash(entry.ClientIP) + " - - [" + entry.Time.Format(combinedTimeLayout) + "] " +
+		strconv.Quote(requestLine) + " " + strconv.Itoa(entry.Status) + " " + bytesOut + " " +
+		strconv.Quote(valueOrDash(entry.Referer)) + " " + strconv.Quote(valueOrDash(entry.UserAgent))
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+type accessLogWriter struct {
+	writer io.Writer
+	mu     sync.Mutex
+}
+
+func (logWriter *accessLogWriter) writeLine(line []byte) {
+	logWriter.mu.Lock()
+	_, _ = logWriter.writer.Write(append(line, '\n'))
+	logWriter.mu.Unlock()
+}
+
+type AccessLogInterceptor struct {
+	enabled    bool
+	format     string
+	sampleRate float64
+	excludes   []string
+	writer     *accessLogWriter
+}
+
+func NewAccessLogInterceptor(properties *AccessLogProperties) AccessLogInterceptor {
+	interceptor := AccessLogInterceptor{
+		enabled:    false,
+		format:     AccessLogFormatJson,
+		sampleRate: 1,
+		excludes:   make([]string, 0),
+		writer: &accessLogWriter{
+			writer: os.Stdout,
+		},
+	}
+
+	if properties == nil {
+		return interceptor
+	}
+
+	interceptor.enabled = properties.Enabled
+	interceptor.sampleRate = properties.SampleRate
+
+	if properties.Format != "" {
+		if properties.Format != AccessLogFormatJson && properties.Format != AccessLogFormatCombined {
+			panic("Unsupported access log format : " + properties.Format)
+		}
+		interceptor.format = properties.Format
+	}
+
+	for _, pattern := range strings.Split(properties.Exclude, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern != "" {
+			interceptor.excludes = append(interceptor.excludes, pattern)
+		}
+	}
+	return interceptor
+}
+
+func (interceptor AccessLogInterceptor) WithWriter(writer io.Writer) AccessLogInterceptor {
+	if writer == nil {
+		panic("Writer must not be null")
+	}
+
+	interceptor.writer = &accessLogWriter{
+		writer: writer,
+	}
+	return interceptor
+}
+
+func (interceptor AccessLogInterceptor) GetPriority() core.PriorityValue {
+	return core.PriorityHighest
+}
+
+func (interceptor AccessLogInterceptor) InterceptsUnmatchedRequests() bool {
+	return interceptor.enabled
+}
+
+func (interceptor AccessLogInterceptor) HandleBefore(ctx *WebRequestContext) {
+	if interceptor.enabled {
+		ctx.Put(accessLogStartTimeKey, time.Now())
+	}
+}
+
+func (interceptor AccessLogInterceptor) AfterCompletion(ctx *WebRequestContext) {
+	startTime, ok := ctx.Get(accessLogStartTimeKey).(time.Time)
+	if !ok {
+		return
+	}
+
+	requestPath := ctx.GetPath()
+	if interceptor.isExcluded(requestPath) {
+		return
+	}
+
+	requestCtx := ctx.fastHttpRequestContext
+	status := requestCtx.Response.StatusCode()
+	if status < 400 && !interceptor.isSampled() {
+		return
+	}
+
+	bytesOut := 0
+	if !requestCtx.Response.IsBodyStream() {
+		bytesOut = len(requestCtx.Response.Body())
+	}
+
+	entry := AccessLogEntry{
+		Time:      startTime,
+		ContextId: ctx.contextIdStr,
+		Method:    string(requestCtx.Method()),
+		Route:     ctx.handlerChain.GetPattern(),
+		Path:      requestPath,
+		Query:     string(requestCtx.URI().QueryString()),
+		Protocol:  string(requestCtx.Request.Header.Protocol()),
+		Status:    status,
+		LatencyMs: float64(time.Since(startTime)) / float64(time.Millisecond),
+		BytesIn:   len(requestCtx.Request.Body()),
+		BytesOut:  bytesOut,
+		ClientIP:  ctx.ClientIP().String(),
+		UserAgent: string(requestCtx.UserAgent()),
+		Referer:   string(requestCtx.Referer()),
+	}
+
+	if interceptor.format == AccessLogFormatCombined {
+		interceptor.writer.writeLine([]byte(entry.formatCombined()))
+		return
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	interceptor.writer.writeLine(line)
+}
+
+func (interceptor AccessLogInterceptor) isExcluded(requestPath string) bool {
+	for _, pattern := range interceptor.excludes {
+		if matchPathPattern(pattern, requestPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func (interceptor AccessLogInterceptor) isSampled() bool {
+	if interceptor.sampleRate >= 1 {
+		return true
+	}
+
+	if interceptor.sampleRate <= 0 {
+		return false
+	}
+	return rand.Float64() < interceptor.sampleRate
+}
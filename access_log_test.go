@@ -0,0 +1,95 @@
+package web
+
+import (
+	"bytes"
+	json "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAccessLogInterceptor_Json(t *testing.T) {
+	var buffer bytes.Buffer
+	interceptor := NewAccessLogInterceptor(&AccessLogProperties{
+		Enabled:    true,
+		Format:     AccessLogFormatJson,
+		SampleRate: 1,
+		Exclude:    "/health/**, /metrics",
+	}).WithWriter(&buffer)
+
+	router := NewRouter(
+		WithControllers(testNetHttpController{}),
+		WithManagementControllers(NewHealthController(NewSimpleHealthIndicatorRegistry(), nil)),
+		WithInterceptors(interceptor),
+	)
+
+	serveTestAdapterRequest(router, http.MethodPost, "/echo?debug=true", map[string]string{
+		"User-Agent":    "procyon-test",
+		HeaderRequestId: "gateway-request-1",
+	}, "payload")
+	serveTestAdapterRequest(router, http.MethodGet, "/health/liveness", nil, "")
+	serveTestAdapterRequest(router, http.MethodGet, "/unknown", nil, "")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	notFoundEntry := AccessLogEntry{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[1]), &notFoundEntry))
+	assert.Equal(t, http.StatusNotFound, notFoundEntry.Status)
+	assert.Equal(t, "", notFoundEntry.Route)
+	assert.Equal(t, "/unknown", notFoundEntry.Path)
+
+	entry := AccessLogEntry{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "gateway-request-1", entry.ContextId)
+	assert.Equal(t, http.MethodPost, entry.Method)
+	assert.Equal(t, "/echo", entry.Route)
+	assert.Equal(t, "/echo", entry.Path)
+	assert.Equal(t, "debug=true", entry.Query)
+	assert.Equal(t, http.StatusCreated, entry.Status)
+	assert.Equal(t, 7, entry.BytesIn)
+	assert.Equal(t, 8, entry.BytesOut)
+	assert.Equal(t, "0.0.0.0", entry.ClientIP)
+	assert.Equal(t, "procyon-test", entry.UserAgent)
+	assert.True(t, entry.LatencyMs >= 0)
+}
+
+func TestAccessLogInterceptor_Combined(t *testing.T) {
+	var buffer bytes.Buffer
+	interceptor := NewAccessLogInterceptor(&AccessLogProperties{
+		Enabled:    true,
+		Format:     AccessLogFormatCombined,
+		SampleRate: 0,
+	}).WithWriter(&buffer)
+
+	router := NewRouter(
+		WithControllers(testNetHttpController{}, testMetricsController{}),
+		WithInterceptors(interceptor),
+	)
+
+	serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", nil, "")
+	assert.Empty(t, buffer.String())
+
+	serveTestAdapterRequest(router, http.MethodGet, "/fail", map[string]string{
+		"User-Agent": "curl/7.68.0",
+		"Referer":    "https://procyon.dev",
+	}, "")
+
+	line := strings.TrimSpace(buffer.String())
+	assert.Regexp(t, `^0\.0\.0\.0 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /fail HTTP/1.1" 500 \d+ "https://procyon.dev" "curl/7.68.0"$`, line)
+}
+
+func TestAccessLogInterceptor_Disabled(t *testing.T) {
+	var buffer bytes.Buffer
+	interceptor := NewAccessLogInterceptor(&AccessLogProperties{Enabled: false, SampleRate: 1}).WithWriter(&buffer)
+	router := NewRouter(WithControllers(testNetHttpController{}), WithInterceptors(interceptor))
+
+	serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", nil, "")
+	serveTestAdapterRequest(router, http.MethodGet, "/unknown", nil, "")
+	assert.Empty(t, buffer.String())
+
+	router = NewRouter(WithControllers(testNetHttpController{}), WithInterceptors(NewAccessLogInterceptor(nil).WithWriter(&buffer)))
+	serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", nil, "")
+	assert.Empty(t, buffer.String())
+}
@@ -0,0 +1,265 @@
+package web
+
+import (
+	"bytes"
+	stdcontext "context"
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+	"net/http"
+	"strings"
+)
+
+type HTTPMiddleware func(next http.Handler) http.Handler
+
+type webRequestContextKey struct {
+}
+
+func WrapHandler(handler http.Handler) RequestHandlerFunction {
+	if handler == nil {
+		panic("Handler must not be null")
+	}
+
+	return func(ctx *WebRequestContext) {
+		request, err := ctx.GetHTTPRequest()
+		if err != nil {
+			ctx.ThrowError(HttpErrorBadRequest.WithDetail(err.Error()))
+		}
+
+		writer := newHTTPResponseWriter()
+		handler.ServeHTTP(writer, request)
+		writer.writeTo(ctx)
+	}
+}
+
+func WrapHandlerFunc(handlerFunc http.HandlerFunc) RequestHandlerFunction {
+	return WrapHandler(handlerFunc)
+}
+
+func Mount(prefix string, handler http.Handler) []RequestHandler {
+	handlerFunc := WrapHandler(handler)
+	path := strings.TrimSuffix(prefix, "/") + "/*"
+
+	methods := []func(handler RequestHandlerFunction, options ...RequestHandlerOption) RequestHandler{
+		Get, Post, Put, Delete, Patch, Options, Head,
+	}
+
+	handlers := make([]RequestHandler, 0, len(methods))
+	for _, method := range methods {
+		handlers = append(handlers, method(handlerFunc, Path(path)))
+	}
+	return handlers
+}
+
+func GetWebRequestContext(request *http.Request) (*WebRequestContext, bool) {
+	ctx, ok := request.Context().Value(webRequestContextKey{}).(*WebRequestContext)
+	return ctx, ok
+}
+
+func (ctx *WebRequestContext) GetHTTPRequest() (*http.Request, error) {
+	if ctx.httpRequest != nil {
+		return ctx.httpRequest, nil
+	}
+
+	request := &http.Request{}
+	if err := fasthttpadaptor.ConvertRequest(ctx.fastHttpRequestContext, request, true); err != nil {
+		return nil, err
+	}
+
+	ctx.httpRequest = request.WithContext(stdcontext.WithValue(request.Context(), webRequestContextKey{}, ctx))
+	return ctx.httpRequest, nil
+}
+
+type MiddlewareInterceptor struct {
+	middleware HTTPMiddleware
+	priority   core.PriorityValue
+}
+
+func WrapMiddleware(middleware HTTPMiddleware) MiddlewareInterceptor {
+	if middleware == nil {
+		panic("Middleware must not be null")
+	}
+
+	return MiddlewareInterceptor{
+		middleware: middleware,
+		priority:   core.PriorityLowest,
+	}
+}
+
+func (interceptor MiddlewareInterceptor) WithPriority(priority core.PriorityValue) MiddlewareInterceptor {
+	interceptor.priority = priority
+	return interceptor
+}
+
+func (interceptor MiddlewareInterceptor) GetPriority() core.PriorityValue {
+	return interceptor.priority
+}
+
+func (interceptor MiddlewareInterceptor) HandleBefore(ctx *WebRequestContext) {
+	request, err := ctx.GetHTTPRequest()
+	if err != nil {
+		ctx.SetHTTPError(HttpErrorBadRequest.WithDetail(err.Error()))
+		ctx.Cancel()
+		return
+	}
+
+	var nextRequest *http.Request
+	writer := newHTTPResponseWriter()
+	next := http.HandlerFunc(func(nextWriter http.ResponseWriter, request *http.Request) {
+		nextRequest = request
+		if nextWriter == http.ResponseWriter(writer) && writer.statusCode == 0 {
+			return
+		}
+
+		writer.writeHeadersTo(ctx)
+		ctx.updateHTTPRequest(request)
+		ctx.invokeNextHandlers()
+		ctx.writeResponseTo(nextWriter)
+	})
+
+	interceptor.middleware(next).ServeHTTP(writer, request)
+
+	if nextRequest == nil {
+		writer.writeTo(ctx)
+		ctx.Cancel()
+		return
+	}
+
+	if ctx.completed {
+		writer.writeTo(ctx)
+		return
+	}
+
+	writer.writeHeadersTo(ctx)
+	ctx.updateHTTPRequest(nextRequest)
+}
+
+func (ctx *WebRequestContext) invokeNextHandlers() {
+	ctx.handlerIndex++
+	ctx.invokeHandlersUntil(ctx.handlerChain.afterCompletionStartIndex)
+	ctx.handlerIndex = ctx.handlerChain.afterCompletionStartIndex - 1
+}
+
+func (ctx *WebRequestContext) writeResponseTo(writer http.ResponseWriter) {
+	response := &ctx.fastHttpRequestContext.Response
+	responseHeader := make(http.Header)
+	response.Header.VisitAll(func(key, value []byte) {
+		if name := string(key); name != fasthttp.HeaderContentLength {
+			responseHeader.Add(name, string(value))
+		}
+	})
+
+	header := writer.Header()
+	for key, values := range responseHeader {
+		header[key] = values
+	}
+
+	writer.WriteHeader(response.StatusCode())
+	_, _ = writer.Write(response.Body())
+}
+
+func (ctx *WebRequestContext) updateHTTPRequest(request *http.Request) {
+	fastHttpRequest := &ctx.fastHttpRequestContext.Request
+	requestHeader := &fastHttpRequest.Header
+
+	removedKeys := make([]string, 0)
+	requestHeader.VisitAll(func(key, value []byte) {
+		name := string(key)
+		if name == fasthttp.HeaderTransferEncoding {
+			return
+		}
+
+		if _, ok := request.Header[name]; !ok {
+			removedKeys = append(removedKeys, name)
+		}
+	})
+
+	for _, key := range removedKeys {
+		requestHeader.Del(key)
+	}
+
+	for key, values := range request.Header {
+		requestHeader.Del(key)
+		for _, value := range values {
+			requestHeader.Add(key, value)
+		}
+	}
+
+	if request.Method != "" && request.Method != string(requestHeader.Method()) {
+		requestHeader.SetMethod(request.Method)
+	}
+
+	if request.Host != "" && request.Host != string(fastHttpRequest.Host()) {
+		fastHttpRequest.SetHost(request.Host)
+	}
+
+	if request.URL != nil {
+		if requestURI := request.URL.RequestURI(); requestURI != string(fastHttpRequest.RequestURI()) {
+			fastHttpRequest.SetRequestURI(requestURI)
+			ctx.uri = nil
+			ctx.path = nil
+		}
+	}
+
+	if _, ok := GetWebRequestContext(request); !ok {
+		request = request.WithContext(stdcontext.WithValue(request.Context(), webRequestContextKey{}, ctx))
+	}
+	ctx.httpRequest = request
+}
+
+type httpResponseWriter struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func newHTTPResponseWriter() *httpResponseWriter {
+	return &httpResponseWriter{
+		header: make(http.Header),
+	}
+}
+
+func (writer *httpResponseWriter) Header() http.Header {
+	return writer.header
+}
+
+func (writer *httpResponseWriter) WriteHeader(statusCode int) {
+	if writer.statusCode == 0 {
+		writer.statusCode = statusCode
+	}
+}
+
+func (writer *httpResponseWriter) Write(data []byte) (int, error) {
+	if writer.statusCode == 0 {
+		writer.statusCode = http.StatusOK
+	}
+	return writer.body.Write(data)
+}
+
+func (writer *httpResponseWriter) writeHeadersTo(ctx *WebRequestContext) {
+	responseHeader := &ctx.fastHttpRequestContext.Response.Header
+	for key, values := range writer.header {
+		responseHeader.Del(key)
+		for _, value := range values {
+			responseHeader.Add(key, value)
+		}
+	}
+}
+
+func (writer *httpResponseWriter) writeTo(ctx *WebRequestContext) {
+	statusCode := writer.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	if writer.header.Get("Content-Type") == "" && writer.body.Len() != 0 {
+		writer.header.Set("Content-Type", http.DetectContentType(writer.body.Bytes()))
+	}
+
+	writer.writeHeadersTo(ctx)
+	ctx.fastHttpRequestContext.SetStatusCode(statusCode)
+	ctx.fastHttpRequestContext.SetBody(writer.body.Bytes())
+
+	ctx.responseEntity.status = statusCode
+	ctx.responseWritten = true
+}
@@ -0,0 +1,212 @@
+package web
+
+import (
+	"bytes"
+	stdcontext "context"
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+type testAdapterContextKey struct {
+}
+
+type testAdapterController struct {
+}
+
+func (controller testAdapterController) RegisterHandlers(registry HandlerRegistry) {
+	registry.Register(
+		Post(WrapHandlerFunc(controller.createItem), Path("/items/:id")),
+		Get(controller.getItem, Path("/items/:id")),
+	)
+	registry.Register(Mount("/legacy", controller.legacyMux())...)
+}
+
+func (controller testAdapterController) createItem(writer http.ResponseWriter, request *http.Request) {
+	ctx, _ := GetWebRequestContext(request)
+	id, _ := ctx.GetPathVariable("id")
+	body, _ := ioutil.ReadAll(request.Body)
+
+	writer.Header().Set("Content-Type", "text/plain")
+	writer.Header().Set("X-Item", id)
+	writer.WriteHeader(http.StatusCreated)
+	_, _ = writer.Write([]byte(request.Method + " " + request.URL.Path + " " + string(body)))
+}
+
+func (controller testAdapterController) getItem(ctx *WebRequestContext) {
+	request, _ := ctx.GetHTTPRequest()
+	user, _ := request.Context().Value(testAdapterContextKey{}).(string)
+	header, _ := ctx.GetRequestHeader("X-Authenticated-User")
+	ctx.Ok().SetModel(user + ":" + header)
+}
+
+func (controller testAdapterController) legacyMux() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/legacy/status", func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte("legacy " + request.Method))
+	})
+	return mux
+}
+
+func testAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("X-Trace-Id", "trace-1")
+		if request.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(writer, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		request.Header.Set("X-Authenticated-User", "procyon")
+		next.ServeHTTP(writer, request.WithContext(stdcontext.WithValue(request.Context(), testAdapterContextKey{}, "procyon")))
+	})
+}
+
+func serveTestAdapterRequest(router *ProcyonRouter, method string, uri string, headers map[string]string, body string) *fasthttp.Response {
+	requestCtx := &fasthttp.RequestCtx{}
+	requestCtx.Request.Header.SetMethod(method)
+	requestCtx.Request.SetRequestURI(uri)
+	for key, value := range headers {
+		requestCtx.Request.Header.Set(key, value)
+	}
+	requestCtx.Request.SetBodyString(body)
+
+	router.Route(requestCtx)
+	return &requestCtx.Response
+}
+
+func TestWrapHandler(t *testing.T) {
+	router := NewRouter(WithControllers(testAdapterController{}))
+
+	response := serveTestAdapterRequest(router, http.MethodPost, "/items/5", nil, "payload")
+	assert.Equal(t, http.StatusCreated, response.StatusCode())
+	assert.Equal(t, "text/plain", string(response.Header.ContentType()))
+	assert.Equal(t, "5", string(response.Header.Peek("X-Item")))
+	assert.Equal(t, "POST /items/5 payload", string(response.Body()))
+}
+
+func TestMount(t *testing.T) {
+	router := NewRouter(WithControllers(testAdapterController{}))
+
+	response := serveTestAdapterRequest(router, http.MethodPut, "/legacy/status", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.Equal(t, "legacy PUT", string(response.Body()))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/legacy/unknown", nil, "")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode())
+}
+
+func TestWrapMiddleware(t *testing.T) {
+	router := NewRouter(
+		WithControllers(testAdapterController{}),
+		WithInterceptors(WrapMiddleware(testAuthMiddleware)),
+	)
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/items/5", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode())
+	assert.Equal(t, "trace-1", string(response.Header.Peek("X-Trace-Id")))
+	assert.Equal(t, "unauthorized\n", string(response.Body()))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/items/5", map[string]string{"Authorization": "Bearer secret"}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.Equal(t, "trace-1", string(response.Header.Peek("X-Trace-Id")))
+	assert.Equal(t, "procyon:procyon", string(response.Body()))
+}
+
+type testRewriteController struct {
+}
+
+func (controller testRewriteController) RegisterHandlers(registry HandlerRegistry) {
+	registry.Register(Get(controller.getRewritten, Path("/rewrite")))
+}
+
+func (controller testRewriteController) getRewritten(ctx *WebRequestContext) {
+	_, removed := ctx.GetRequestHeader("X-Remove")
+	lang, _ := ctx.GetRequestParameter("lang")
+	ctx.Ok().SetModel(strconv.FormatBool(removed) + ":" + lang)
+}
+
+func testRewriteMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		request.Header.Del("X-Remove")
+		request.URL.RawQuery = "lang=tr"
+		next.ServeHTTP(writer, request)
+	})
+}
+
+type testStatusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (recorder *testStatusRecorder) WriteHeader(statusCode int) {
+	recorder.statusCode = statusCode
+	recorder.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (recorder *testStatusRecorder) Write(data []byte) (int, error) {
+	return recorder.ResponseWriter.Write(bytes.ToUpper(data))
+}
+
+func testWrappingMiddleware(statusCodes *[]int) HTTPMiddleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			recorder := &testStatusRecorder{ResponseWriter: writer}
+			next.ServeHTTP(recorder, request)
+			*statusCodes = append(*statusCodes, recorder.statusCode)
+		})
+	}
+}
+
+func testEarlyWriteMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusAccepted)
+		_, _ = writer.Write([]byte("accepted:"))
+		next.ServeHTTP(writer, request)
+	})
+}
+
+func TestWrapMiddleware_UpdatesRequest(t *testing.T) {
+	router := NewRouter(
+		WithControllers(testRewriteController{}),
+		WithInterceptors(WrapMiddleware(testRewriteMiddleware)),
+	)
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/rewrite?lang=en", map[string]string{"X-Remove": "true"}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.Equal(t, "false:tr", string(response.Body()))
+}
+
+func TestWrapMiddleware_WrappedResponseWriter(t *testing.T) {
+	statusCodes := make([]int, 0)
+	calls := make([]string, 0)
+	router := NewRouter(
+		WithControllers(testRewriteController{}),
+		WithInterceptors(
+			WrapMiddleware(testWrappingMiddleware(&statusCodes)).WithPriority(core.PriorityHighest),
+			WrapMiddleware(testRewriteMiddleware),
+			testRecoveryInterceptor{&calls},
+		),
+	)
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/rewrite", map[string]string{"X-Remove": "true"}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.Equal(t, MediaTypeApplicationTextHtmlValue, string(response.Header.ContentType()))
+	assert.Equal(t, "FALSE:TR", string(response.Body()))
+	assert.Equal(t, []int{http.StatusOK}, statusCodes)
+	assert.Equal(t, []string{"before", "after", "afterCompletion:200"}, calls)
+}
+
+func TestWrapMiddleware_WritesResponseBeforeNext(t *testing.T) {
+	router := NewRouter(
+		WithControllers(testRewriteController{}),
+		WithInterceptors(WrapMiddleware(testEarlyWriteMiddleware)),
+	)
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/rewrite?lang=en", nil, "")
+	assert.Equal(t, http.StatusAccepted, response.StatusCode())
+	assert.Equal(t, "accepted:false:en", string(response.Body()))
+}
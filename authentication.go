@@ -0,0 +1,380 @@
+package web
+
+import (
+	"encoding/base64"
+	"errors"
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/valyala/fasthttp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	AuthenticationSchemeBasic  = "Basic"
+	AuthenticationSchemeBearer = "Bearer"
+	AuthenticationSchemeAPIKey = "ApiKey"
+
+	DefaultAuthenticationRealm = "procyon"
+	DefaultAPIKeyHeader        = "X-API-Key"
+)
+
+const AuthenticationInterceptorPriority = core.PriorityHighest + 100
+
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidToken       = errors.New("invalid token")
+)
+
+type Principal interface {
+	GetName() string
+}
+
+type SimplePrincipal struct {
+	Name string
+}
+
+func (principal SimplePrincipal) GetName() string {
+	return principal.Name
+}
+
+type Authentication struct {
+	Principal   Principal
+	Scheme      string
+	Authorities []string
+	Attributes  map[string]interface{}
+}
+
+func NewAuthentication(principal Principal, authorities ...string) *Authentication {
+	return &Authentication{
+		Principal:   principal,
+		Authorities: authorities,
+		Attributes:  make(map[string]interface{}),
+	}
+}
+
+func (authentication *Authentication) HasAuthority(authority string) bool {
+	for _, grantedAuthority := range authentication.Authorities {
+		if grantedAuthority == authority {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctx *WebRequestContext) GetAuthentication() *Authentication {
+	return ctx.authentication
+}
+
+func (ctx *WebRequestContext) SetAuthentication(authentication *Authentication) {
+	ctx.authentication = authentication
+}
+
+func (ctx *WebRequestContext) GetPrincipal() Principal {
+	if ctx.authentication == nil {
+		return nil
+	}
+	return ctx.authentication.Principal
+}
+
+func (ctx *WebRequestContext) IsAuthenticated() bool {
+	return ctx.authentication != nil && ctx.authentication.Principal != nil
+}
+
+type Authenticator interface {
+	GetScheme() string
+	GetChallenge(err error) string
+	Authenticate(ctx *WebRequestContext) (*Authentication, error)
+}
+
+type BasicCredentialsValidator func(username string, password string) (*Authentication, error)
+
+type TokenValidator func(token string) (*Authentication, error)
+
+func parseAuthorizationHeader(ctx *WebRequestContext, scheme string) (string, bool) {
+	value, ok := ctx.GetRequestHeader(fasthttp.HeaderAuthorization)
+	if !ok || len(value) <= len(scheme) || !strings.EqualFold(value[:len(scheme)], scheme) || value[len(scheme)] != ' ' {
+		return "", false
+	}
+	return strings.TrimSpace(value[len(scheme)+1:]), true
+}
+
+func formatChallenge(scheme string, realm string, params ...string) string {
+	challenge := scheme + " realm=" + strconv.Quote(realm)
+	for index := 0; index+1 < len(params); index += 2 {
+		challenge += ", " + params[index] + "=" + strconv.Quote(params[index+1])
+	}
+	return challenge
+}
+
+type BasicAuthenticator struct {
+	realm     string
+	validator BasicCredentialsValidator
+}
+
+func NewBasicAuthenticator(validator BasicCredentialsValidator) BasicAuthenticator {
+	if validator == nil {
+		panic("Validator must not be null")
+	}
+
+	return BasicAuthenticator{
+		realm:     DefaultAuthenticationRealm,
+		validator: validator,
+	}
+}
+
+func (authenticator BasicAuthenticator) WithRealm(realm string) BasicAuthenticator {
+	authenticator.realm = realm
+	return authenticator
+}
+
+func (authenticator BasicAuthenticator) GetScheme() string {
+	return AuthenticationSchemeBasic
+}
+
+func (authenticator BasicAuthenticator) GetChallenge(err error) string {
+	return formatChallenge(AuthenticationSchemeBasic, authenticator.realm, "charset", "UTF-8")
+}
+
+func (authenticator BasicAuthenticator) Authenticate(ctx *WebRequestContext) (*Authentication, error) {
+	encodedCredentials, ok := parseAuthorizationHeader(ctx, AuthenticationSchemeBasic)
+	if !ok {
+		return nil, nil
+	}
+
+	credentials, err := base64.StdEncoding.DecodeString(encodedCredentials)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	separatorIndex := strings.IndexByte(string(credentials), ':')
+	if separatorIndex == -1 {
+		return nil, ErrInvalidCredentials
+	}
+	return authenticator.validator(string(credentials[:separatorIndex]), string(credentials[separatorIndex+1:]))
+}
+
+type BearerAuthenticator struct {
+	realm     string
+	validator TokenValidator
+}
+
+func NewBearerAuthenticator(validator TokenValidator) BearerAuthenticator {
+	if validator == nil {
+		panic("Validator must not be null")
+	}
+
+	return BearerAuthenticator{
+		realm:     DefaultAuthenticationRealm,
+		validator: validator,
+	}
+}
+
+func (authenticator BearerAuthenticator) WithRealm(realm string) BearerAuthenticator {
+	authenticator.realm = realm
+	return authenticator
+}
+
+func (authenticator BearerAuthenticator) GetScheme() string {
+	return AuthenticationSchemeBearer
+}
+
+func (authenticator BearerAuthenticator) GetChallenge(err error) string {
+	if err == nil {
+		return formatChallenge(AuthenticationSchemeBearer, authenticator.realm)
+	}
+	return formatChallenge(AuthenticationSchemeBearer, authenticator.realm, "error", "invalid_token")
+}
+
+func (authenticator BearerAuthenticator) Authenticate(ctx *WebRequestContext) (*Authentication, error) {
+	token, ok := parseAuthorizationHeader(ctx, AuthenticationSchemeBearer)
+	if !ok {
+		return nil, nil
+	}
+
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+	return authenticator.validator(token)
+}
+
+type APIKeyAuthenticator struct {
+	headerName    string
+	parameterName string
+	validator     TokenValidator
+}
+
+func NewAPIKeyAuthenticator(validator TokenValidator) APIKeyAuthenticator {
+	if validator == nil {
+		panic("Validator must not be null")
+	}
+
+	return APIKeyAuthenticator{
+		headerName: DefaultAPIKeyHeader,
+		validator:  validator,
+	}
+}
+
+func (authenticator APIKeyAuthenticator) WithHeaderName(headerName string) APIKeyAuthenticator {
+	authenticator.headerName = headerName
+	return authenticator
+}
+
+func (authenticator APIKeyAuthenticator) WithParameterName(parameterName string) APIKeyAuthenticator {
+	authenticator.parameterName = parameterName
+	return authenticator
+}
+
+func (authenticator APIKeyAuthenticator) GetScheme() string {
+	return AuthenticationSchemeAPIKey
+}
+
+func (authenticator APIKeyAuthenticator) GetChallenge(err error) string {
+	return ""
+}
+
+func (authenticator APIKeyAuthenticator) Authenticate(ctx *WebRequestContext) (*Authentication, error) {
+	if authenticator.headerName != "" {
+		if apiKey, ok := ctx.GetRequestHeader(authenticator.headerName); ok && apiKey != "" {
+			return authenticator.validator(apiKey)
+		}
+	}
+
+	if authenticator.parameterName != "" {
+		if apiKey, ok := ctx.GetRequestParameter(authenticator.parameterName); ok && apiKey != "" {
+			return authenticator.validator(apiKey)
+		}
+	}
+	return nil, nil
+}
+
+type AuthenticatorRegistry interface {
+	RegisterAuthenticator(authenticators ...Authenticator)
+	GetAuthenticators() []Authenticator
+}
+
+type SimpleAuthenticatorRegistry struct {
+	authenticators []Authenticator
+	mu             sync.RWMutex
+}
+
+func NewSimpleAuthenticatorRegistry() *SimpleAuthenticatorRegistry {
+	return &SimpleAuthenticatorRegistry{
+		authenticators: make([]Authenticator, 0),
+	}
+}
+
+func (registry *SimpleAuthenticatorRegistry) RegisterAuthenticator(authenticators ...Authenticator) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for _, authenticator := range authenticators {
+		if authenticator == nil {
+			panic("Authenticator must not be null")
+		}
+		registry.authenticators = append(registry.authenticators, authenticator)
+	}
+
+	sort.SliceStable(registry.authenticators, func(i, j int) bool {
+		return authenticatorPriority(registry.authenticators[i]) < authenticatorPriority(registry.authenticators[j])
+	})
+}
+
+func authenticatorPriority(authenticator Authenticator) core.PriorityValue {
+	if priority, ok := authenticator.(core.Priority); ok {
+		return priority.GetPriority()
+	}
+	return core.PriorityLowest
+}
+
+func (registry *SimpleAuthenticatorRegistry) GetAuthenticators() []Authenticator {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	authenticators := make([]Authenticator, len(registry.authenticators))
+	copy(authenticators, registry.authenticators)
+	return authenticators
+}
+
+type AuthenticationInterceptor struct {
+	registry AuthenticatorRegistry
+	enabled  bool
+	required bool
+}
+
+func NewAuthenticationInterceptor(registry AuthenticatorRegistry, properties *AuthenticationProperties) AuthenticationInterceptor {
+	if registry == nil {
+		panic("Authenticator registry must not be null")
+	}
+
+	interceptor := AuthenticationInterceptor{
+		registry: registry,
+		enabled:  true,
+	}
+
+	if properties != nil {
+		interceptor.enabled = properties.Enabled
+		interceptor.required = properties.Required
+	}
+	return interceptor
+}
+
+func (interceptor AuthenticationInterceptor) WithRequired(required bool) AuthenticationInterceptor {
+	interceptor.required = required
+	return interceptor
+}
+
+func (interceptor AuthenticationInterceptor) GetPriority() core.PriorityValue {
+	return AuthenticationInterceptorPriority
+}
+
+func (interceptor AuthenticationInterceptor) HandleBefore(ctx *WebRequestContext) {
+	if !interceptor.enabled {
+		return
+	}
+
+	authenticators := interceptor.registry.GetAuthenticators()
+	for index, authenticator := range authenticators {
+		authentication, err := authenticator.Authenticate(ctx)
+		if err == nil && authentication != nil && authentication.Principal == nil {
+			err = ErrInvalidCredentials
+		}
+
+		if err != nil {
+			challengeAuthentication(ctx, authenticators, index, err)
+			return
+		}
+
+		if authentication != nil {
+			if authentication.Scheme == "" {
+				authentication.Scheme = authenticator.GetScheme()
+			}
+			ctx.SetAuthentication(authentication)
+			return
+		}
+	}
+
+	if interceptor.required {
+		challengeAuthentication(ctx, authenticators, -1, nil)
+	}
+}
+
+func challengeAuthentication(ctx *WebRequestContext, authenticators []Authenticator, failedIndex int, err error) {
+	for index, authenticator := range authenticators {
+		var challenge string
+		if index == failedIndex {
+			challenge = authenticator.GetChallenge(err)
+		} else {
+			challenge = authenticator.GetChallenge(nil)
+		}
+
+		if challenge != "" {
+			ctx.AddResponseHeader(fasthttp.HeaderWWWAuthenticate, challenge)
+		}
+	}
+
+	ctx.SetAuthentication(nil)
+	ctx.SetHTTPError(HttpErrorUnauthorized)
+	ctx.Cancel()
+}
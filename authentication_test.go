@@ -0,0 +1,123 @@
+package web
+
+import (
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type testAuthenticationController struct {
+}
+
+func (controller testAuthenticationController) RegisterHandlers(registry HandlerRegistry) {
+	registry.Register(Get(controller.getMe, Path("/me")))
+}
+
+func (controller testAuthenticationController) getMe(ctx *WebRequestContext) {
+	name := "anonymous"
+	if ctx.IsAuthenticated() {
+		name = ctx.GetAuthentication().Scheme + ":" + ctx.GetPrincipal().GetName()
+	}
+	ctx.Ok().SetModel(name)
+}
+
+func testBasicCredentialsValidator(username string, password string) (*Authentication, error) {
+	if username != "procyon" || password != "secret" {
+		return nil, ErrInvalidCredentials
+	}
+	return NewAuthentication(SimplePrincipal{username}, "ROLE_USER"), nil
+}
+
+func testTokenValidator(token string) (*Authentication, error) {
+	if token != "valid-token" {
+		return nil, ErrInvalidToken
+	}
+	return NewAuthentication(SimplePrincipal{"service"}), nil
+}
+
+func newTestAuthenticationRouter(required bool) *ProcyonRouter {
+	registry := NewSimpleAuthenticatorRegistry()
+	registry.RegisterAuthenticator(
+		NewBasicAuthenticator(testBasicCredentialsValidator),
+		NewBearerAuthenticator(testTokenValidator),
+		NewAPIKeyAuthenticator(testTokenValidator).WithParameterName("api_key"),
+	)
+
+	interceptor := NewAuthenticationInterceptor(registry, &AuthenticationProperties{Enabled: true, Required: required})
+	return NewRouter(WithControllers(testAuthenticationController{}), WithInterceptors(interceptor))
+}
+
+func TestAuthenticationInterceptor_Basic(t *testing.T) {
+	router := newTestAuthenticationRouter(false)
+	credentials := base64.StdEncoding.EncodeToString([]byte("procyon:secret"))
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/me", map[string]string{"Authorization": "Basic " + credentials}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.Equal(t, "Basic:procyon", string(response.Body()))
+
+	credentials = base64.StdEncoding.EncodeToString([]byte("procyon:wrong"))
+	response = serveTestAdapterRequest(router, http.MethodGet, "/me", map[string]string{"Authorization": "Basic " + credentials}, "")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode())
+
+	challenges := make([]string, 0)
+	response.Header.VisitAll(func(key, value []byte) {
+		if string(key) == "Www-Authenticate" {
+			challenges = append(challenges, string(value))
+		}
+	})
+	assert.Equal(t, []string{`Basic realm="procyon", charset="UTF-8"`, `Bearer realm="procyon"`}, challenges)
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/me", map[string]string{"Authorization": "Basic not-base64"}, "")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode())
+}
+
+func TestAuthenticationInterceptor_BearerAndAPIKey(t *testing.T) {
+	router := newTestAuthenticationRouter(false)
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/me", map[string]string{"Authorization": "bearer valid-token"}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.Equal(t, "Bearer:service", string(response.Body()))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/me", map[string]string{"Authorization": "Bearer expired"}, "")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode())
+	assert.Contains(t, string(response.Header.Peek("WWW-Authenticate")), "Basic")
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/me", map[string]string{DefaultAPIKeyHeader: "valid-token"}, "")
+	assert.Equal(t, "ApiKey:service", string(response.Body()))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/me?api_key=valid-token", nil, "")
+	assert.Equal(t, "ApiKey:service", string(response.Body()))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/me?api_key=unknown", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode())
+}
+
+func TestAuthenticationInterceptor_Anonymous(t *testing.T) {
+	response := serveTestAdapterRequest(newTestAuthenticationRouter(false), http.MethodGet, "/me", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.Equal(t, "anonymous", string(response.Body()))
+
+	response = serveTestAdapterRequest(newTestAuthenticationRouter(true), http.MethodGet, "/me", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode())
+	assert.Equal(t, `Basic realm="procyon", charset="UTF-8"`, string(response.Header.Peek("WWW-Authenticate")))
+}
+
+func TestAuthenticationInterceptor_RunsBeforeRateLimiting(t *testing.T) {
+	rateLimitInterceptor := NewRateLimitInterceptor(&RateLimitProperties{Enabled: true, Limit: 1, Period: 60, Key: RateLimitKeyPrincipal})
+	router := NewRouter(
+		WithControllers(testAuthenticationController{}),
+		WithInterceptors(rateLimitInterceptor),
+		WithAuthenticators(NewBearerAuthenticator(testTokenValidator)),
+	)
+
+	headers := map[string]string{"Authorization": "Bearer valid-token"}
+	response := serveTestAdapterRequest(router, http.MethodGet, "/me", headers, "")
+	assert.Equal(t, "Bearer:service", string(response.Body()))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/me", headers, "")
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/me", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+}
@@ -12,6 +12,60 @@ type RequestBinder interface {
 	BindRequest(request interface{}, ctx *WebRequestContext) error
 }
 
+const (
+	BindingSourceBody   = "body"
+	BindingSourceParam  = "param"
+	BindingSourcePath   = "path"
+	BindingSourceHeader = "header"
+)
+
+type BindingError struct {
+	Source string
+	Name   string
+	Err    error
+}
+
+func newBindingError(source string, name string, err error) *BindingError {
+	return &BindingError{
+		Source: source,
+		Name:   name,
+		Err:    err,
+	}
+}
+
+func (err *BindingError) Error() string {
+	if err.Name == "" {
+		return "request " + err.Source + " cannot be bound : " + err.Err.Error()
+	}
+	return "request " + err.Source + " '" + err.Name + "' cannot be bound : " + err.Err.Error()
+}
+
+func (err *BindingError) Unwrap() error {
+	return err.Err
+}
+
+func (err *BindingError) toProblemError() ProblemError {
+	return ProblemError{
+		Source: err.Source,
+		Field:  err.Name,
+		Detail: err.Err.Error(),
+	}
+}
+
+func convertRequestValue(source string, name string, value string, converter valueConverterFunction) (result reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if recoveredErr, ok := r.(error); ok {
+				err = newBindingError(source, name, recoveredErr)
+			} else {
+				err = newBindingError(source, name, errors.New("value cannot be converted"))
+			}
+		}
+	}()
+
+	return reflect.ValueOf(converter(value)), nil
+}
+
 type defaultRequestBinder struct {
 }
 
@@ -48,12 +102,12 @@ func (binder defaultRequestBinder) BindRequest(request interface{}, ctx *WebRequ
 		if contentType == MediaTypeApplicationJsonValue {
 			err := json.Unmarshal(body, request)
 			if err != nil {
-				return err
+				return newBindingError(BindingSourceBody, "", err)
 			}
 		} else {
 			err := xml.Unmarshal(body, request)
 			if err != nil {
-				return err
+				return newBindingError(BindingSourceBody, "", err)
 			}
 		}
 		return nil
@@ -74,12 +128,12 @@ func (binder defaultRequestBinder) BindRequest(request interface{}, ctx *WebRequ
 		if contentType == MediaTypeApplicationJsonValue {
 			err := json.Unmarshal(body, bodyValue.Addr().Interface())
 			if err != nil {
-				return err
+				return newBindingError(BindingSourceBody, "", err)
 			}
 		} else if contentType == MediaTypeApplicationXmlValue {
 			err := xml.Unmarshal(body, bodyValue.Addr().Interface())
 			if err != nil {
-				return err
+				return newBindingError(BindingSourceBody, "", err)
 			}
 		}
 	}
@@ -94,7 +148,11 @@ func (binder defaultRequestBinder) BindRequest(request interface{}, ctx *WebRequ
 			}
 
 			if fieldMetadata.converter != nil {
-				paramField.Set(reflect.ValueOf(fieldMetadata.converter(paramValue)))
+				convertedValue, err := convertRequestValue(BindingSourceParam, tagValue, paramValue, fieldMetadata.converter)
+				if err != nil {
+					return err
+				}
+				paramField.Set(convertedValue)
 			} else {
 				paramField.SetString(paramValue)
 			}
@@ -103,7 +161,7 @@ func (binder defaultRequestBinder) BindRequest(request interface{}, ctx *WebRequ
 
 	if metadata.pathMetadata.fieldIndex != -1 {
 		pathStruct := val.Field(metadata.pathMetadata.fieldIndex)
-		for _, fieldMetadata := range metadata.pathMetadata.pathVariableMap {
+		for tagValue, fieldMetadata := range metadata.pathMetadata.pathVariableMap {
 			pathField := pathStruct.Field(fieldMetadata.index)
 			if fieldMetadata.extra == -1 {
 				continue
@@ -111,7 +169,11 @@ func (binder defaultRequestBinder) BindRequest(request interface{}, ctx *WebRequ
 
 			pathVariableValue := ctx.pathVariables[fieldMetadata.extra]
 			if fieldMetadata.converter != nil {
-				pathField.Set(reflect.ValueOf(fieldMetadata.converter(pathVariableValue)))
+				convertedValue, err := convertRequestValue(BindingSourcePath, tagValue, pathVariableValue, fieldMetadata.converter)
+				if err != nil {
+					return err
+				}
+				pathField.Set(convertedValue)
 			} else {
 				pathField.SetString(pathVariableValue)
 			}
@@ -128,7 +190,11 @@ func (binder defaultRequestBinder) BindRequest(request interface{}, ctx *WebRequ
 			}
 
 			if fieldMetadata.converter != nil {
-				headerField.Set(reflect.ValueOf(fieldMetadata.converter(headerValue)))
+				convertedValue, err := convertRequestValue(BindingSourceHeader, tagValue, headerValue, fieldMetadata.converter)
+				if err != nil {
+					return err
+				}
+				headerField.Set(convertedValue)
 			} else {
 				headerField.SetString(headerValue)
 			}
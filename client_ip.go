@@ -0,0 +1,283 @@
+package web
+
+import (
+	"bytes"
+	"net"
+	"strings"
+)
+
+const (
+	HeaderForwarded       = "Forwarded"
+	HeaderXForwardedFor   = "X-Forwarded-For"
+	HeaderXForwardedProto = "X-Forwarded-Proto"
+	HeaderXForwardedHost  = "X-Forwarded-Host"
+	HeaderXRealIP         = "X-Real-IP"
+)
+
+const (
+	ForwardedHeaderForwarded     = "forwarded"
+	ForwardedHeaderXForwardedFor = "x-forwarded-for"
+	ForwardedHeaderXRealIP       = "x-real-ip"
+)
+
+type forwardedElement struct {
+	forValue string
+	proto    string
+	host     string
+}
+
+func ParseTrustedProxies(values ...string) ([]*net.IPNet, error) {
+	return parseNetworks(values...)
+}
+
+func parseNetworks(values ...string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: value}
+			}
+
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, ipNet)
+	}
+	return networks, nil
+}
+
+func parseForwardedHeader(value string) string {
+	switch header := strings.ToLower(strings.TrimSpace(value)); header {
+	case "":
+		return ForwardedHeaderXForwardedFor
+	case ForwardedHeaderForwarded, ForwardedHeaderXForwardedFor, ForwardedHeaderXRealIP:
+		return header
+	default:
+		panic("Unsupported forwarded header : " + value)
+	}
+}
+
+func (router *ProcyonRouter) isTrustedProxy(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, trustedProxy := range router.trustedProxies {
+		if trustedProxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctx *WebRequestContext) remoteIP() net.IP {
+	if ctx.fastHttpRequestContext == nil {
+		return nil
+	}
+	return ctx.fastHttpRequestContext.RemoteIP()
+}
+
+func (ctx *WebRequestContext) isFromTrustedProxy() bool {
+	return ctx.router != nil && ctx.router.isTrustedProxy(ctx.remoteIP())
+}
+
+func (ctx *WebRequestContext) ClientIP() net.IP {
+	if element, ok := ctx.trustedForwardedElement(); ok && element.forValue != "" {
+		if ip := parseForwardedIP(element.forValue); ip != nil {
+			return ip
+		}
+	}
+	return ctx.remoteIP()
+}
+
+func (ctx *WebRequestContext) Scheme() string {
+	if element, ok := ctx.trustedForwardedElement(); ok && element.proto != "" {
+		return strings.ToLower(element.proto)
+	}
+
+	if ctx.fastHttpRequestContext == nil {
+		return "http"
+	}
+
+	if ctx.fastHttpRequestContext.IsTLS() {
+		return "https"
+	}
+	return string(ctx.fastHttpRequestContext.URI().Scheme())
+}
+
+func (ctx *WebRequestContext) Host() string {
+	if element, ok := ctx.trustedForwardedElement(); ok && element.host != "" {
+		return element.host
+	}
+
+	if ctx.fastHttpRequestContext == nil {
+		return ""
+	}
+	return string(ctx.fastHttpRequestContext.Host())
+}
+
+func (ctx *WebRequestContext) trustedForwardedElement() (forwardedElement, bool) {
+	if !ctx.isFromTrustedProxy() {
+		return forwardedElement{}, false
+	}
+
+	elements := ctx.forwardedElements()
+	chosenIndex := -1
+	for index := len(elements) - 1; index >= 0; index-- {
+		hopIP := parseForwardedIP(elements[index].forValue)
+		if hopIP == nil {
+			break
+		}
+
+		chosenIndex = index
+		if !ctx.router.isTrustedProxy(hopIP) {
+			break
+		}
+	}
+
+	if chosenIndex == -1 {
+		if ctx.router.forwardedHeader == ForwardedHeaderForwarded {
+			return forwardedElement{}, false
+		}
+
+		element := forwardedElement{
+			proto: ctx.forwardedHeaderValue(HeaderXForwardedProto, 0, 0),
+			host:  ctx.forwardedHeaderValue(HeaderXForwardedHost, 0, 0),
+		}
+		return element, element.proto != "" || element.host != ""
+	}
+	return elements[chosenIndex], true
+}
+
+func (ctx *WebRequestContext) peekRequestHeader(key string) string {
+	if ctx.fastHttpRequestContext == nil {
+		return ""
+	}
+	return string(ctx.fastHttpRequestContext.Request.Header.Peek(key))
+}
+
+func (ctx *WebRequestContext) requestHeaderValues(key string) []string {
+	values := make([]string, 0)
+	if ctx.fastHttpRequestContext == nil {
+		return values
+	}
+
+	keyBytes := []byte(key)
+	ctx.fastHttpRequestContext.Request.Header.VisitAll(func(headerKey, value []byte) {
+		if bytes.EqualFold(headerKey, keyBytes) {
+			values = append(values, string(value))
+		}
+	})
+	return values
+}
+
+func (ctx *WebRequestContext) forwardedElements() []forwardedElement {
+	elements := make([]forwardedElement, 0)
+	switch ctx.router.forwardedHeader {
+	case ForwardedHeaderForwarded:
+		for _, value := range ctx.requestHeaderValues(HeaderForwarded) {
+			elements = append(elements, parseForwarded(value)...)
+		}
+		return elements
+	case ForwardedHeaderXRealIP:
+		if realIP := strings.TrimSpace(ctx.peekRequestHeader(HeaderXRealIP)); realIP != "" {
+			elements = append(elements, forwardedElement{forValue: realIP})
+		}
+	default:
+		for _, value := range ctx.requestHeaderValues(HeaderXForwardedFor) {
+			for _, hop := range strings.Split(value, ",") {
+				elements = append(elements, forwardedElement{forValue: strings.TrimSpace(hop)})
+			}
+		}
+	}
+
+	for index := range elements {
+		elements[index].proto = ctx.forwardedHeaderValue(HeaderXForwardedProto, index, len(elements))
+		elements[index].host = ctx.forwardedHeaderValue(HeaderXForwardedHost, index, len(elements))
+	}
+	return elements
+}
+
+func (ctx *WebRequestContext) forwardedHeaderValue(key string, hopIndex int, hopCount int) string {
+	values := make([]string, 0)
+	for _, value := range ctx.requestHeaderValues(key) {
+		for _, item := range strings.Split(value, ",") {
+			values = append(values, strings.TrimSpace(item))
+		}
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+
+	valueIndex := len(values) - (hopCount - hopIndex)
+	if valueIndex < 0 {
+		valueIndex = 0
+	} else if valueIndex >= len(values) {
+		valueIndex = len(values) - 1
+	}
+	return values[valueIndex]
+}
+
+func parseForwarded(value string) []forwardedElement {
+	elements := make([]forwardedElement, 0)
+	for _, elementValue := range strings.Split(value, ",") {
+		element := forwardedElement{}
+		for _, pair := range strings.Split(elementValue, ";") {
+			separatorIndex := strings.Index(pair, "=")
+			if separatorIndex == -1 {
+				continue
+			}
+
+			key := strings.ToLower(strings.TrimSpace(pair[:separatorIndex]))
+			pairValue := strings.Trim(strings.TrimSpace(pair[separatorIndex+1:]), "\"")
+			switch key {
+			case "for":
+				element.forValue = pairValue
+			case "proto":
+				element.proto = pairValue
+			case "host":
+				element.host = pairValue
+			}
+		}
+		elements = append(elements, element)
+	}
+	return elements
+}
+
+func parseForwardedIP(value string) net.IP {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") {
+		closingIndex := strings.Index(value, "]")
+		if closingIndex == -1 {
+			return nil
+		}
+		return net.ParseIP(value[1:closingIndex])
+	}
+
+	if ip := net.ParseIP(value); ip != nil {
+		return ip
+	}
+
+	host, _, err := net.SplitHostPort(value)
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
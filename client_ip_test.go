@@ -0,0 +1,174 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	trustedProxies, err := ParseTrustedProxies("10.0.0.0/8", " 192.168.1.10 ", "", "2001:db8::/32")
+	assert.Nil(t, err)
+	assert.Len(t, trustedProxies, 3)
+	assert.Equal(t, "192.168.1.10/32", trustedProxies[1].String())
+
+	_, err = ParseTrustedProxies("10.0.0.0/33")
+	assert.NotNil(t, err)
+
+	_, err = ParseTrustedProxies("proxy.local")
+	assert.NotNil(t, err)
+}
+
+func TestWebRequestContext_ClientIPWithoutTrustedProxies(t *testing.T) {
+	ctx := NewWebRequestContext(RequestMethodGet, "/orders",
+		ContextRemoteAddr("10.0.0.1:8080"),
+		ContextHeader(HeaderXForwardedFor, "203.0.113.7"),
+		ContextHeader(HeaderXRealIP, "203.0.113.8"),
+	)
+	assert.Equal(t, "10.0.0.1", ctx.ClientIP().String())
+}
+
+func TestWebRequestContext_ClientIPFromXForwardedFor(t *testing.T) {
+	ctx := NewWebRequestContext(RequestMethodGet, "/orders",
+		ContextTrustedProxies("10.0.0.0/8"),
+		ContextRemoteAddr("10.0.0.1:8080"),
+		ContextHeader(HeaderXForwardedFor, "198.51.100.1, 203.0.113.7"),
+		ContextHeader(HeaderXForwardedFor, "10.0.0.2"),
+	)
+	assert.Equal(t, "203.0.113.7", ctx.ClientIP().String())
+
+	ctx = NewWebRequestContext(RequestMethodGet, "/orders",
+		ContextTrustedProxies("10.0.0.0/8"),
+		ContextRemoteAddr("203.0.113.9:8080"),
+		ContextHeader(HeaderXForwardedFor, "198.51.100.1"),
+	)
+	assert.Equal(t, "203.0.113.9", ctx.ClientIP().String())
+
+	ctx = NewWebRequestContext(RequestMethodGet, "/orders",
+		ContextTrustedProxies("10.0.0.0/8"),
+		ContextRemoteAddr("10.0.0.1:8080"),
+		ContextHeader(HeaderXForwardedFor, "10.0.0.3, 10.0.0.2"),
+	)
+	assert.Equal(t, "10.0.0.3", ctx.ClientIP().String())
+
+	ctx = NewWebRequestContext(RequestMethodGet, "/orders",
+		ContextTrustedProxies("10.0.0.0/8"),
+		ContextRemoteAddr("10.0.0.1:8080"),
+		ContextHeader(HeaderXForwardedFor, "unknown, 10.0.0.2"),
+	)
+	assert.Equal(t, "10.0.0.2", ctx.ClientIP().String())
+}
+
+func TestWebRequestContext_ClientIPFromForwarded(t *testing.T) {
+	ctx := NewWebRequestContext(RequestMethodGet, "/orders",
+		ContextTrustedProxies("10.0.0.0/8"),
+		ContextForwardedHeader(ForwardedHeaderForwarded),
+		ContextRemoteAddr("10.0.0.1:8080"),
+		ContextHeader(HeaderForwarded, "for=\"[2001:db8:cafe::17]:4711\";proto=https;host=api.procyon.io, for=10.0.0.2"),
+		ContextHeader(HeaderXForwardedFor, "198.51.100.1"),
+	)
+	assert.Equal(t, "2001:db8:cafe::17", ctx.ClientIP().String())
+	assert.Equal(t, "https", ctx.Scheme())
+	assert.Equal(t, "api.procyon.io", ctx.Host())
+
+	ctx = NewWebRequestContext(RequestMethodGet, "http://internal.local/orders",
+		ContextTrustedProxies("10.0.0.0/8"),
+		ContextForwardedHeader(ForwardedHeaderForwarded),
+		ContextRemoteAddr("10.0.0.1:8080"),
+		ContextHeader(HeaderForwarded, "for=198.51.100.66;proto=http;host=evil.example, for=203.0.113.7;proto=https;host=api.procyon.io"),
+	)
+	assert.Equal(t, "203.0.113.7", ctx.ClientIP().String())
+	assert.Equal(t, "https", ctx.Scheme())
+	assert.Equal(t, "api.procyon.io", ctx.Host())
+
+	ctx = NewWebRequestContext(RequestMethodGet, "/orders",
+		ContextTrustedProxies("10.0.0.0/8"),
+		ContextRemoteAddr("10.0.0.1:8080"),
+		ContextHeader(HeaderForwarded, "for=198.51.100.66"),
+		ContextHeader(HeaderXForwardedFor, "203.0.113.7"),
+	)
+	assert.Equal(t, "203.0.113.7", ctx.ClientIP().String())
+}
+
+func TestWebRequestContext_ClientIPFromXRealIP(t *testing.T) {
+	ctx := NewWebRequestContext(RequestMethodGet, "/orders",
+		ContextTrustedProxies("10.0.0.1"),
+		ContextForwardedHeader(ForwardedHeaderXRealIP),
+		ContextRemoteAddr("10.0.0.1:8080"),
+		ContextHeader(HeaderXRealIP, "203.0.113.8"),
+		ContextHeader(HeaderXForwardedFor, "198.51.100.1"),
+	)
+	assert.Equal(t, "203.0.113.8", ctx.ClientIP().String())
+
+	ctx = NewWebRequestContext(RequestMethodGet, "/orders",
+		ContextTrustedProxies("10.0.0.1"),
+		ContextRemoteAddr("10.0.0.1:8080"),
+		ContextHeader(HeaderXRealIP, "203.0.113.8"),
+	)
+	assert.Equal(t, "10.0.0.1", ctx.ClientIP().String())
+}
+
+func TestWebRequestContext_SchemeAndHost(t *testing.T) {
+	ctx := NewWebRequestContext(RequestMethodGet, "http://internal.local/orders",
+		ContextRemoteAddr("203.0.113.9:8080"),
+		ContextHeader(HeaderXForwardedProto, "https"),
+		ContextHeader(HeaderXForwardedHost, "api.procyon.io"),
+	)
+	assert.Equal(t, "http", ctx.Scheme())
+	assert.Equal(t, "internal.local", ctx.Host())
+
+	ctx = NewWebRequestContext(RequestMethodGet, "http://internal.local/orders",
+		ContextTrustedProxies("10.0.0.0/8"),
+		ContextRemoteAddr("10.0.0.1:8080"),
+		ContextHeader(HeaderXForwardedFor, "198.51.100.66, 203.0.113.7, 10.0.0.2"),
+		ContextHeader(HeaderXForwardedProto, "http, HTTPS, http"),
+		ContextHeader(HeaderXForwardedHost, "evil.example, api.procyon.io, internal.local"),
+	)
+	assert.Equal(t, "203.0.113.7", ctx.ClientIP().String())
+	assert.Equal(t, "https", ctx.Scheme())
+	assert.Equal(t, "api.procyon.io", ctx.Host())
+
+	ctx = NewWebRequestContext(RequestMethodGet, "http://internal.local/orders",
+		ContextTrustedProxies("10.0.0.0/8"),
+		ContextRemoteAddr("10.0.0.1:8080"),
+		ContextHeader(HeaderXForwardedFor, "203.0.113.7"),
+		ContextHeader(HeaderXForwardedProto, "https"),
+		ContextHeader(HeaderXForwardedHost, "api.procyon.io"),
+	)
+	assert.Equal(t, "https", ctx.Scheme())
+	assert.Equal(t, "api.procyon.io", ctx.Host())
+}
+
+func TestWebRequestContext_ClientIPWithoutForwardedFor(t *testing.T) {
+	ctx := NewWebRequestContext(RequestMethodGet, "http://internal.local/orders",
+		ContextTrustedProxies("10.0.0.0/8"),
+		ContextRemoteAddr("10.0.0.1:8080"),
+		ContextHeader(HeaderXForwardedProto, "https"),
+	)
+	assert.Equal(t, "10.0.0.1", ctx.ClientIP().String())
+	assert.Equal(t, "https", ctx.Scheme())
+
+	ctx = NewWebRequestContext(RequestMethodGet, "http://internal.local/orders",
+		ContextTrustedProxies("10.0.0.0/8"),
+		ContextRemoteAddr("10.0.0.1:8080"),
+		ContextHeader(HeaderXForwardedHost, "api.procyon.io"),
+	)
+	assert.Equal(t, "10.0.0.1", ctx.ClientIP().String())
+	assert.Equal(t, "api.procyon.io", ctx.Host())
+}
+
+func TestRouter_TrustedProxies(t *testing.T) {
+	assert.Panics(t, func() {
+		NewRouter(WithTrustedProxies("not-a-cidr/8"))
+	})
+
+	assert.Panics(t, func() {
+		NewRouter(WithForwardedHeader("x-client-ip"))
+	})
+
+	router := NewRouter(WithTrustedProxies("0.0.0.0/32"))
+	ctx := router.newWebRequestContext().(*WebRequestContext)
+	ctx.fastHttpRequestContext = NewWebRequestContext(RequestMethodGet, "/orders",
+		ContextHeader(HeaderXForwardedFor, "203.0.113.7"),
+	).fastHttpRequestContext
+	assert.Equal(t, "203.0.113.7", ctx.ClientIP().String())
+}
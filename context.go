@@ -1,6 +1,7 @@
 package web
 
 import (
+	"fmt"
 	"github.com/procyon-projects/goo"
 	configure "github.com/procyon-projects/procyon-configure"
 	"github.com/procyon-projects/procyon-context"
@@ -9,11 +10,16 @@ import (
 	"github.com/valyala/fasthttp"
 	"net/http"
 	"strconv"
+	"sync"
 )
 
 type ProcyonServerApplicationContext struct {
 	*context.BaseApplicationContext
-	server Server
+	router           *ProcyonRouter
+	server           Server
+	managementServer Server
+	stopOnce         sync.Once
+	stopErr          error
 }
 
 func NewProcyonServerApplicationContext(appId context.ApplicationId, contextId context.ContextId) *ProcyonServerApplicationContext {
@@ -27,6 +33,10 @@ func (ctx *ProcyonServerApplicationContext) GetWebServer() Server {
 	return ctx.server
 }
 
+func (ctx *ProcyonServerApplicationContext) GetManagementServer() Server {
+	return ctx.managementServer
+}
+
 func (ctx *ProcyonServerApplicationContext) Configure() {
 	ctx.BaseApplicationContext.Configure()
 }
@@ -43,7 +53,7 @@ func (ctx *ProcyonServerApplicationContext) initializeInterceptors() {
 
 	for _, peaName := range peaNames {
 		peaDefinition := peaDefinitionRegistry.GetPeaDefinition(peaName)
-		if peaDefinition != nil && !ctx.isHandlerInterceptor(peaDefinition.GetPeaType()) {
+		if peaDefinition != nil && !ctx.isHandlerInterceptor(peaDefinition.GetPeaType()) && !ctx.isAuthenticator(peaDefinition.GetPeaType()) {
 			continue
 		}
 		peaFactory.GetPea(peaName)
@@ -69,13 +79,40 @@ func (ctx *ProcyonServerApplicationContext) isHandlerInterceptor(typ goo.Type) b
 	return false
 }
 
+func (ctx *ProcyonServerApplicationContext) isAuthenticator(typ goo.Type) bool {
+	peaType := typ
+	if peaType.IsFunction() {
+		peaType = peaType.ToFunctionType().GetFunctionReturnTypes()[0]
+	}
+
+	if peaType.IsStruct() {
+		return peaType.ToStructType().Implements(goo.GetType((*Authenticator)(nil)).ToInterfaceType())
+	}
+	return false
+}
+
 func (ctx *ProcyonServerApplicationContext) FinishConfigure() {
 	logger := ctx.GetLogger()
 	startedChannel := make(chan bool, 1)
 	go func() {
 		serverProperties := ctx.GetSharedPeaType(goo.GetType((*configure.WebServerProperties)(nil)))
 		ctx.server.SetProperties(serverProperties.(*configure.WebServerProperties))
-		logger.Info(ctx, "Procyon started on port(s): "+strconv.Itoa(int(ctx.GetWebServer().GetPort())))
+		ctx.createManagementServer(serverProperties.(*configure.WebServerProperties))
+
+		ports := strconv.Itoa(int(ctx.GetWebServer().GetPort()))
+		if ctx.managementServer != nil {
+			ports += ", " + strconv.Itoa(int(ctx.managementServer.GetPort())) + " (management)"
+			go func() {
+				if err := ctx.managementServer.Run(); err != nil {
+					logger.Error(ctx, "Management server failed : "+err.Error())
+				}
+			}()
+		}
+
+		ctx.AddApplicationListener(serverShutdownListener{ctx})
+		ctx.handleShutdownSignals()
+
+		logger.Info(ctx, "Procyon started on port(s): "+ports)
 		startedChannel <- true
 		ctx.server.Run()
 	}()
@@ -83,10 +120,44 @@ func (ctx *ProcyonServerApplicationContext) FinishConfigure() {
 }
 
 func (ctx *ProcyonServerApplicationContext) createWebServer() error {
-	ctx.server = newProcyonWebServer(ctx.BaseApplicationContext)
+	ctx.router = NewProcyonRouter(ctx.BaseApplicationContext)
+	ctx.server = newWebServer(ctx.router, ctx.BaseApplicationContext.GetPeaFactory())
 	return nil
 }
 
+func (ctx *ProcyonServerApplicationContext) createManagementServer(serverProperties *configure.WebServerProperties) {
+	peaFactory := ctx.BaseApplicationContext.GetPeaFactory()
+	managementRegistry, _ := peaFactory.GetPeaByType(goo.GetType((*ManagementRegistry)(nil)))
+	if managementRegistry == nil {
+		return
+	}
+	registry := managementRegistry.(ManagementRegistry)
+
+	managementProperties, _ := peaFactory.GetPeaByType(goo.GetType((*ManagementServerProperties)(nil)))
+	var properties *ManagementServerProperties
+	if managementProperties != nil {
+		properties = managementProperties.(*ManagementServerProperties)
+	}
+
+	port, onMainPort := resolveManagementPort(properties, serverProperties.Port)
+	if onMainPort {
+		handlerMapping := peaFactory.GetSharedPeaType(goo.GetType((*HandlerMapping)(nil)))
+		registerManagementControllers(handlerMapping.(RequestHandlerMapping), registry)
+		return
+	}
+
+	if port == 0 {
+		return
+	}
+
+	ctx.managementServer = newWebServer(newManagementRouterFrom(ctx.router, registry), peaFactory)
+	ctx.managementServer.SetProperties(&configure.WebServerProperties{
+		Port:            port,
+		Shutdown:        serverProperties.Shutdown,
+		ShutdownTimeout: serverProperties.ShutdownTimeout,
+	})
+}
+
 type PathVariable struct {
 	Key   string
 	Value string
@@ -109,22 +180,40 @@ type WebRequestContext struct {
 	pathVariables     [20]string
 	pathVariableCount int
 	// response and error
-	responseWriter ResponseWriter
-	responseEntity ResponseEntity
-	httpError      *HTTPError
-	internalError  error
+	responseWriter  ResponseWriter
+	responseEntity  ResponseEntity
+	responseWritten bool
+	httpError       *HTTPError
+	internalError   error
+	// tracing
+	traceContext TraceContext
+	trace        *requestTrace
+	// security
+	authentication *Authentication
 	// other
-	valueMap  map[string]interface{}
-	canceled  bool
-	completed bool
-	crashed   bool
+	httpRequest *http.Request
+	locale      string
+	valueMap    map[string]interface{}
+	canceled    bool
+	completed   bool
+	crashed     bool
 }
 
 func (ctx *WebRequestContext) prepare(generateContextId bool) {
-	if generateContextId {
-		core.GenerateUUID(ctx.contextIdBuffer[:])
-		ctx.contextIdStr = core.BytesToStr(ctx.contextIdBuffer[:])
+	ctx.prepareTrace()
+
+	if !ctx.prepareRequestId() {
+		if ctx.traceContext.TraceId != "" {
+			copy(ctx.contextIdBuffer[:], ctx.traceContext.TraceId)
+			ctx.contextIdStr = core.BytesToStr(ctx.contextIdBuffer[:traceIdLength])
+		} else if generateContextId {
+			core.GenerateUUID(ctx.contextIdBuffer[:])
+			ctx.contextIdStr = core.BytesToStr(ctx.contextIdBuffer[:])
+		}
 	}
+
+	ctx.echoRequestId()
+	ctx.startTrace()
 }
 
 func (ctx *WebRequestContext) reset() {
@@ -140,6 +229,12 @@ func (ctx *WebRequestContext) reset() {
 	ctx.handlerIndex = 0
 	ctx.pathVariableCount = 0
 	ctx.valueMap = nil
+	ctx.responseWritten = false
+	ctx.locale = ""
+	ctx.httpRequest = nil
+	ctx.traceContext = TraceContext{}
+	ctx.trace = nil
+	ctx.authentication = nil
 	ctx.responseEntity.status = http.StatusOK
 	ctx.responseEntity.model = nil
 	ctx.responseEntity.contentType = DefaultMediaType
@@ -147,6 +242,10 @@ func (ctx *WebRequestContext) reset() {
 }
 
 func (ctx *WebRequestContext) writeResponse() {
+	if ctx.responseWritten {
+		return
+	}
+
 	err := ctx.router.responseBodyWriter.WriteResponseBody(ctx, ctx.responseWriter)
 	if err != nil {
 		panic(err)
@@ -163,6 +262,8 @@ func (ctx *WebRequestContext) writeResponse() {
 		ctx.fastHttpRequestContext.SetContentType(MediaTypeApplicationJsonValue)
 	case MediaTypeApplicationTextHtml:
 		ctx.fastHttpRequestContext.SetContentType(MediaTypeApplicationTextHtmlValue)
+	case MediaTypeApplicationProblemJson:
+		ctx.fastHttpRequestContext.SetContentType(MediaTypeApplicationProblemJsonValue)
 	default:
 		ctx.fastHttpRequestContext.SetContentType(MediaTypeApplicationXmlValue)
 	}
@@ -178,18 +279,32 @@ func (ctx *WebRequestContext) invoke() {
 }
 
 func (ctx *WebRequestContext) invokeHandlers() {
+	ctx.invokeHandlersUntil(ctx.handlerChain.handlerEndIndex + 1)
+}
+
+func (ctx *WebRequestContext) invokeHandlersUntil(endIndex int) {
 next:
-	if ctx.handlerIndex > ctx.handlerChain.handlerEndIndex {
+	if ctx.handlerIndex >= endIndex {
 		return
 	}
 
-	ctx.handlerChain.handlers[ctx.handlerIndex](ctx)
+	if ctx.trace != nil {
+		ctx.traceHandler(ctx.handlerIndex)
+	}
+
+	if ctx.handlerIndex != ctx.handlerChain.handlerIndex || ctx.authorize() {
+		ctx.handlerChain.handlers[ctx.handlerIndex](ctx)
+	} else {
+		ctx.canceled = true
+		ctx.handlerIndex = ctx.handlerChain.afterCompletionStartIndex - 1
+	}
+
 	if ctx.handlerIndex < ctx.handlerChain.handlerIndex && ctx.canceled {
 		ctx.handlerIndex = ctx.handlerChain.afterCompletionStartIndex - 1
 	}
 
 	ctx.handlerIndex++
-	if ctx.handlerIndex == ctx.handlerChain.afterCompletionStartIndex {
+	if ctx.handlerIndex == ctx.handlerChain.afterCompletionStartIndex && !ctx.completed {
 		if ctx.internalError == nil && ctx.httpError != nil {
 			ctx.router.errorHandlerManager.JustHandleError(ctx.httpError, ctx)
 		}
@@ -200,6 +315,25 @@ next:
 	goto next
 }
 
+func (ctx *WebRequestContext) invokeAfterCompletionHandlers() {
+	if ctx.handlerChain == nil || ctx.handlerIndex >= ctx.handlerChain.afterCompletionStartIndex {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil && ctx.router.logger != nil {
+			ctx.router.logger.Error(ctx, fmt.Sprintf("After completion interceptor failed : %v", r))
+		}
+	}()
+
+	for ctx.handlerIndex = ctx.handlerChain.afterCompletionStartIndex; ctx.handlerIndex <= ctx.handlerChain.handlerEndIndex; ctx.handlerIndex++ {
+		if ctx.trace != nil {
+			ctx.traceHandler(ctx.handlerIndex)
+		}
+		ctx.handlerChain.handlers[ctx.handlerIndex](ctx)
+	}
+}
+
 func (ctx *WebRequestContext) Cancel() {
 	if ctx.handlerIndex < ctx.handlerChain.handlerIndex {
 		ctx.canceled = true
@@ -215,6 +349,9 @@ func (ctx *WebRequestContext) Get(key string) interface{} {
 }
 
 func (ctx *WebRequestContext) Put(key string, value interface{}) {
+	if ctx.valueMap == nil {
+		ctx.valueMap = make(map[string]interface{})
+	}
 	ctx.valueMap[key] = value
 }
 
@@ -270,12 +407,46 @@ func (ctx *WebRequestContext) GetRequestBody() []byte {
 	return ctx.fastHttpRequestContext.Request.Body()
 }
 
+func (ctx *WebRequestContext) GetLocale() string {
+	if ctx.locale != "" {
+		return ctx.locale
+	}
+
+	if ctx.router != nil && ctx.router.localeResolver != nil && ctx.fastHttpRequestContext != nil {
+		ctx.locale = ctx.router.localeResolver.ResolveLocale(ctx)
+	}
+
+	if ctx.locale == "" {
+		ctx.locale = DefaultLocale
+	}
+	return ctx.locale
+}
+
+func (ctx *WebRequestContext) SetLocale(locale string) {
+	ctx.locale = locale
+}
+
+func (ctx *WebRequestContext) GetMessage(code string, args ...string) (string, bool) {
+	if ctx.router == nil || ctx.router.messageSource == nil {
+		return "", false
+	}
+	return ctx.router.messageSource.GetMessage(ctx.GetLocale(), code, args...)
+}
+
 func (ctx *WebRequestContext) Validate(val interface{}) error {
 	return ctx.router.validator.Validate(val)
 }
 
 func (ctx *WebRequestContext) BindRequest(request interface{}) error {
-	return ctx.router.requestBinder.BindRequest(request, ctx)
+	err := ctx.router.requestBinder.BindRequest(request, ctx)
+	if err != nil {
+		return err
+	}
+
+	if ctx.handlerChain != nil && ctx.handlerChain.validateRequest {
+		return ctx.Validate(request)
+	}
+	return nil
 }
 
 func (ctx *WebRequestContext) SetResponseStatus(status int) ResponseBodyBuilder {
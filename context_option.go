@@ -0,0 +1,220 @@
+package web
+
+import (
+	json "github.com/json-iterator/go"
+	context "github.com/procyon-projects/procyon-context"
+	"github.com/valyala/fasthttp"
+	"net"
+)
+
+type ContextOption func(options *contextOptions)
+
+type contextOptions struct {
+	headers         [][2]string
+	queryParams     [][2]string
+	pathVariables   [][2]string
+	values          map[string]interface{}
+	body            []byte
+	contentType     string
+	requestObject   RequestHandlerObject
+	requestBinder   RequestBinder
+	validator       Validator
+	validate        bool
+	localeResolver  LocaleResolver
+	messageSource   MessageSource
+	remoteAddr      net.Addr
+	authentication  *Authentication
+	trustedProxies  []string
+	forwardedHeader string
+}
+
+func ContextHeader(key string, value string) ContextOption {
+	return func(options *contextOptions) {
+		options.headers = append(options.headers, [2]string{key, value})
+	}
+}
+
+func ContextQueryParameter(name string, value string) ContextOption {
+	return func(options *contextOptions) {
+		options.queryParams = append(options.queryParams, [2]string{name, value})
+	}
+}
+
+func ContextPathVariable(name string, value string) ContextOption {
+	return func(options *contextOptions) {
+		options.pathVariables = append(options.pathVariables, [2]string{name, value})
+	}
+}
+
+func ContextValue(key string, value interface{}) ContextOption {
+	return func(options *contextOptions) {
+		options.values[key] = value
+	}
+}
+
+func ContextBody(body []byte, contentType string) ContextOption {
+	return func(options *contextOptions) {
+		options.body = body
+		options.contentType = contentType
+	}
+}
+
+func ContextJSONBody(body interface{}) ContextOption {
+	return func(options *contextOptions) {
+		encodedBody, err := json.Marshal(body)
+		if err != nil {
+			panic(err)
+		}
+		options.body = encodedBody
+		options.contentType = MediaTypeApplicationJsonValue
+	}
+}
+
+func ContextRequestObject(requestObject RequestHandlerObject) ContextOption {
+	return func(options *contextOptions) {
+		options.requestObject = requestObject
+	}
+}
+
+func ContextBinder(requestBinder RequestBinder) ContextOption {
+	return func(options *contextOptions) {
+		options.requestBinder = requestBinder
+	}
+}
+
+func ContextValidator(validator Validator) ContextOption {
+	return func(options *contextOptions) {
+		options.validator = validator
+	}
+}
+
+func ContextValidate() ContextOption {
+	return func(options *contextOptions) {
+		options.validate = true
+	}
+}
+
+func ContextLocaleResolver(localeResolver LocaleResolver) ContextOption {
+	return func(options *contextOptions) {
+		options.localeResolver = localeResolver
+	}
+}
+
+func ContextMessageSource(messageSource MessageSource) ContextOption {
+	return func(options *contextOptions) {
+		options.messageSource = messageSource
+	}
+}
+
+func ContextRemoteAddr(remoteAddr string) ContextOption {
+	return func(options *contextOptions) {
+		tcpAddr, err := net.ResolveTCPAddr("tcp", remoteAddr)
+		if err != nil {
+			panic(err)
+		}
+		options.remoteAddr = tcpAddr
+	}
+}
+
+func ContextTrustedProxies(trustedProxies ...string) ContextOption {
+	return func(options *contextOptions) {
+		options.trustedProxies = append(options.trustedProxies, trustedProxies...)
+	}
+}
+
+func ContextForwardedHeader(forwardedHeader string) ContextOption {
+	return func(options *contextOptions) {
+		options.forwardedHeader = forwardedHeader
+	}
+}
+
+func ContextAuthentication(authentication *Authentication) ContextOption {
+	return func(options *contextOptions) {
+		options.authentication = authentication
+	}
+}
+
+func NewWebRequestContext(method RequestMethod, path string, options ...ContextOption) *WebRequestContext {
+	contextOptions := &contextOptions{
+		values:         make(map[string]interface{}),
+		requestBinder:  newDefaultRequestBinder(),
+		localeResolver: NewDefaultLocaleResolver(DefaultLocale),
+	}
+
+	for _, option := range options {
+		option(contextOptions)
+	}
+
+	if contextOptions.messageSource == nil {
+		contextOptions.messageSource = defaultMessageSource()
+	}
+
+	if contextOptions.validator == nil {
+		contextOptions.validator = newDefaultValidator(contextOptions.messageSource)
+	}
+
+	logger := context.NewSimpleLogger()
+	router := &ProcyonRouter{
+		logger:              logger,
+		generateContextId:   true,
+		recoveryActive:      true,
+		errorHandlerManager: newErrorHandlerManager(logger),
+		validator:           contextOptions.validator,
+		localeResolver:      contextOptions.localeResolver,
+		messageSource:       contextOptions.messageSource,
+		requestBinder:       contextOptions.requestBinder,
+		responseBodyWriter:  newDefaultResponseBodyWriter(),
+		requestIdHeader:     HeaderRequestId,
+		echoRequestId:       true,
+	}
+	router.configureTrustedProxies(contextOptions.trustedProxies...)
+	router.forwardedHeader = parseForwardedHeader(contextOptions.forwardedHeader)
+
+	ctx := router.newWebRequestContext().(*WebRequestContext)
+	ctx.fastHttpRequestContext = &fasthttp.RequestCtx{}
+	if contextOptions.remoteAddr != nil {
+		ctx.fastHttpRequestContext.SetRemoteAddr(contextOptions.remoteAddr)
+	}
+
+	request := &ctx.fastHttpRequestContext.Request
+	request.Header.SetMethod(string(method))
+	request.SetRequestURI(path)
+
+	for _, header := range contextOptions.headers {
+		request.Header.Add(header[0], header[1])
+	}
+
+	for _, queryParam := range contextOptions.queryParams {
+		request.URI().QueryArgs().Add(queryParam[0], queryParam[1])
+	}
+
+	if contextOptions.body != nil {
+		request.SetBody(contextOptions.body)
+		request.Header.SetContentType(contextOptions.contentType)
+	}
+
+	ctx.prepare(router.generateContextId)
+	ctx.authentication = contextOptions.authentication
+
+	var metadata *RequestObjectMetadata
+	if contextOptions.requestObject != nil {
+		metadata = ScanRequestObjectMetadata(contextOptions.requestObject)
+	}
+
+	ctx.handlerChain = NewHandlerChain(nil, nil, metadata)
+	ctx.handlerChain.method = method
+	ctx.handlerChain.pattern = ctx.GetPath()
+	ctx.handlerChain.validateRequest = contextOptions.validate
+
+	for _, pathVariable := range contextOptions.pathVariables {
+		ctx.handlerChain.updatePathVariableMetadata(len(ctx.handlerChain.pathVariables), pathVariable[0])
+		ctx.handlerChain.pathVariables = append(ctx.handlerChain.pathVariables, pathVariable[0])
+		ctx.addPathVariableValue(pathVariable[1])
+	}
+
+	for key, value := range contextOptions.values {
+		ctx.Put(key, value)
+	}
+
+	return ctx
+}
@@ -0,0 +1,99 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type testOrderRequest struct {
+	Body struct {
+		Product  string `json:"product" validate:"required"`
+		Quantity int    `json:"quantity" validate:"min=1"`
+	} `request:"body"`
+	PathVariables struct {
+		CustomerId int `json:"customerId"`
+	} `request:"path"`
+	Params struct {
+		Source string `json:"source"`
+	} `request:"param"`
+}
+
+func testCreateOrderHandler(ctx *WebRequestContext) {
+	request := &testOrderRequest{}
+	if err := ctx.BindRequest(request); err != nil {
+		ctx.SetResponseStatus(http.StatusBadRequest).SetModel(err.Error())
+		return
+	}
+
+	token, _ := ctx.GetRequestHeader("X-Token")
+	ctx.AddResponseHeader("X-Token", token)
+	ctx.Created("/orders/1").
+		SetModel(map[string]interface{}{
+			"customerId": request.PathVariables.CustomerId,
+			"product":    request.Body.Product,
+			"source":     request.Params.Source,
+			"user":       ctx.Get("user"),
+		}).
+		SetResponseContentType(MediaTypeApplicationJson)
+}
+
+func TestNewWebRequestContext(t *testing.T) {
+	ctx := NewWebRequestContext(RequestMethodPost, "/customers/7/orders",
+		ContextHeader("X-Token", "secret"),
+		ContextQueryParameter("source", "mobile"),
+		ContextPathVariable("customerId", "7"),
+		ContextValue("user", "procyon"),
+		ContextJSONBody(map[string]interface{}{"product": "book", "quantity": 2}),
+		ContextRequestObject(testOrderRequest{}),
+	)
+
+	testCreateOrderHandler(ctx)
+
+	assert.Equal(t, http.StatusCreated, ctx.GetResponseStatus())
+	assert.Equal(t, "/orders/1", ctx.GetResponseLocation())
+	assert.Equal(t, MediaTypeApplicationJson, ctx.GetResponseContentType())
+	assert.Equal(t, map[string]interface{}{
+		"customerId": 7,
+		"product":    "book",
+		"source":     "mobile",
+		"user":       "procyon",
+	}, ctx.GetModel())
+
+	token, _ := ctx.GetResponseHeader("X-Token")
+	assert.Equal(t, "secret", token)
+	customerId, _ := ctx.GetPathVariable("customerId")
+	assert.Equal(t, "7", customerId)
+	assert.Equal(t, "/customers/7/orders", ctx.GetPath())
+}
+
+type testRejectingValidator struct {
+}
+
+func (validator testRejectingValidator) Validate(val interface{}) error {
+	return HttpErrorUnsupportedMediaType
+}
+
+func TestNewWebRequestContext_WithValidator(t *testing.T) {
+	ctx := NewWebRequestContext(RequestMethodPost, "/customers/7/orders",
+		ContextJSONBody(map[string]interface{}{"product": "book", "quantity": 0}),
+		ContextRequestObject(testOrderRequest{}),
+		ContextValidate(),
+	)
+
+	testCreateOrderHandler(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, ctx.GetResponseStatus())
+	assert.Equal(t, "validation failed : quantity must be at least 1", ctx.GetModel())
+
+	ctx = NewWebRequestContext(RequestMethodPost, "/customers/7/orders",
+		ContextBody([]byte("{}"), MediaTypeApplicationJsonValue),
+		ContextRequestObject(testOrderRequest{}),
+		ContextValidator(testRejectingValidator{}),
+		ContextValidate(),
+	)
+
+	testCreateOrderHandler(ctx)
+
+	assert.Equal(t, HttpErrorUnsupportedMediaType.Error(), ctx.GetModel())
+}
@@ -261,3 +261,58 @@ func TestWebRequestContext_BindRequestForXml_WithOnlyBody(t *testing.T) {
 	assert.Equal(t, requestObj.Name, "test")
 	assert.Equal(t, requestObj.Age, 25)
 }
+
+type testRequestObjectWithParam struct {
+	Params struct {
+		Page int `json:"page" yaml:"page"`
+	} `request:"param"`
+}
+
+func TestWebRequestContext_BindRequest_WithInvalidParam(t *testing.T) {
+	ctx := WebRequestContext{
+		router: &ProcyonRouter{
+			requestBinder: newDefaultRequestBinder(),
+		},
+	}
+	ctx.fastHttpRequestContext = &fasthttp.RequestCtx{}
+	ctx.fastHttpRequestContext.Request.SetRequestURI("/orders?page=first")
+
+	requestObj := &testRequestObjectWithParam{}
+	ctx.handlerChain = NewHandlerChain(nil, nil, ScanRequestObjectMetadata(requestObj))
+	err := ctx.BindRequest(requestObj)
+
+	var bindingError *BindingError
+	assert.True(t, errors.As(err, &bindingError))
+	assert.Equal(t, BindingSourceParam, bindingError.Source)
+	assert.Equal(t, "page", bindingError.Name)
+}
+
+type testRequestObjectWithValidation struct {
+	Name string `json:"name" validate:"required"`
+	Age  int    `json:"age" validate:"gte=18"`
+}
+
+func TestWebRequestContext_BindRequest_WithValidation(t *testing.T) {
+	ctx := WebRequestContext{
+		router: &ProcyonRouter{
+			requestBinder: newDefaultRequestBinder(),
+			validator:     newDefaultValidator(nil),
+		},
+	}
+	ctx.fastHttpRequestContext = &fasthttp.RequestCtx{}
+	ctx.fastHttpRequestContext.Request.SetBody([]byte("{\"name\":\"test\",\"age\":12}"))
+	ctx.fastHttpRequestContext.Request.Header.SetContentType(MediaTypeApplicationJsonValue)
+
+	requestObj := &testRequestObjectWithValidation{}
+	ctx.handlerChain = NewHandlerChain(nil, nil, ScanRequestObjectMetadata(requestObj))
+	assert.Nil(t, ctx.BindRequest(requestObj))
+
+	ctx.handlerChain.validateRequest = true
+	err := ctx.BindRequest(requestObj)
+
+	var validationError *ValidationError
+	assert.True(t, errors.As(err, &validationError))
+	assert.Equal(t, []FieldError{
+		{Field: "age", Rule: "gte", Param: "18", Message: "age must be greater than or equal to 18"},
+	}, validationError.Errors)
+}
@@ -0,0 +1,273 @@
+package web
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/valyala/fasthttp"
+	"strings"
+)
+
+const (
+	CSRFModeDoubleSubmit = "double-submit"
+	CSRFModeSynchronizer = "synchronizer"
+
+	DefaultCSRFCookieName = "XSRF-TOKEN"
+	DefaultCSRFHeaderName = "X-XSRF-TOKEN"
+	DefaultCSRFFieldName  = "_csrf"
+
+	csrfTokenSize        = 32
+	csrfStateKey         = "procyon.web.csrf-state"
+	csrfSessionAttribute = "procyon.web.csrf-token"
+	csrfInvalidToken     = "invalid CSRF token"
+)
+
+const CSRFInterceptorPriority = core.PriorityHighest + 150
+
+type CSRFTokenRepository interface {
+	LoadToken(ctx *WebRequestContext) (string, bool)
+	SaveToken(ctx *WebRequestContext, token string) bool
+}
+
+type CookieCSRFTokenRepository struct {
+	cookieName string
+	path       string
+	secure     bool
+}
+
+func NewCookieCSRFTokenRepository(cookieName string) CookieCSRFTokenRepository {
+	if cookieName == "" {
+		cookieName = DefaultCSRFCookieName
+	}
+
+	return CookieCSRFTokenRepository{
+		cookieName: cookieName,
+		path:       "/",
+	}
+}
+
+func (repository CookieCSRFTokenRepository) WithPath(path string) CookieCSRFTokenRepository {
+	repository.path = path
+	return repository
+}
+
+func (repository CookieCSRFTokenRepository) WithSecure(secure bool) CookieCSRFTokenRepository {
+	repository.secure = secure
+	return repository
+}
+
+func (repository CookieCSRFTokenRepository) LoadToken(ctx *WebRequestContext) (string, bool) {
+	token := ctx.fastHttpRequestContext.Request.Header.Cookie(repository.cookieName)
+	if len(token) == 0 {
+		return "", false
+	}
+	return string(token), true
+}
+
+func (repository CookieCSRFTokenRepository) SaveToken(ctx *WebRequestContext, token string) bool {
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	cookie.SetKey(repository.cookieName)
+	cookie.SetValue(token)
+	cookie.SetPath(repository.path)
+	cookie.SetSecure(repository.secure)
+	cookie.SetSameSite(fasthttp.CookieSameSiteLaxMode)
+	ctx.fastHttpRequestContext.Response.Header.SetCookie(cookie)
+	return true
+}
+
+type SessionCSRFTokenRepository struct {
+}
+
+func NewSessionCSRFTokenRepository() SessionCSRFTokenRepository {
+	return SessionCSRFTokenRepository{}
+}
+
+func (repository SessionCSRFTokenRepository) LoadToken(ctx *WebRequestContext) (string, bool) {
+	session := ctx.Session()
+	if session == nil {
+		return "", false
+	}
+
+	token, ok := session.Get(csrfSessionAttribute).(string)
+	return token, ok
+}
+
+func (repository SessionCSRFTokenRepository) SaveToken(ctx *WebRequestContext, token string) bool {
+	session := ctx.Session()
+	if session == nil {
+		return false
+	}
+
+	session.Put(csrfSessionAttribute, token)
+	return true
+}
+
+type csrfState struct {
+	repository CSRFTokenRepository
+	token      string
+}
+
+func generateRandomToken(size int) string {
+	buffer := make([]byte, size)
+	if _, err := rand.Read(buffer); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer)
+}
+
+func (ctx *WebRequestContext) GetCSRFToken() string {
+	state, ok := ctx.Get(csrfStateKey).(*csrfState)
+	if !ok {
+		return ""
+	}
+
+	if state.token != "" {
+		return state.token
+	}
+
+	if token, ok := state.repository.LoadToken(ctx); ok && token != "" {
+		state.token = token
+		return token
+	}
+
+	token := generateRandomToken(csrfTokenSize)
+	if !state.repository.SaveToken(ctx, token) {
+		return ""
+	}
+	state.token = token
+	return token
+}
+
+type CSRFInterceptor struct {
+	enabled    bool
+	repository CSRFTokenRepository
+	headerName string
+	fieldName  string
+	exempts    []string
+}
+
+func NewCSRFInterceptor(properties *CSRFProperties) CSRFInterceptor {
+	interceptor := CSRFInterceptor{
+		enabled:    true,
+		repository: NewCookieCSRFTokenRepository(DefaultCSRFCookieName),
+		headerName: DefaultCSRFHeaderName,
+		fieldName:  DefaultCSRFFieldName,
+		exempts:    make([]string, 0),
+	}
+
+	if properties == nil {
+		return interceptor
+	}
+
+	interceptor.enabled = properties.Enabled
+
+	switch properties.Mode {
+	case "", CSRFModeDoubleSubmit:
+		repository := NewCookieCSRFTokenRepository(properties.CookieName).WithSecure(properties.CookieSecure)
+		if properties.CookiePath != "" {
+			repository = repository.WithPath(properties.CookiePath)
+		}
+		interceptor.repository = repository
+	case CSRFModeSynchronizer:
+		interceptor.repository = NewSessionCSRFTokenRepository()
+	default:
+		panic("Unsupported CSRF mode : " + properties.Mode)
+	}
+
+	if properties.HeaderName != "" {
+		interceptor.headerName = properties.HeaderName
+	}
+
+	if properties.FieldName != "" {
+		interceptor.fieldName = properties.FieldName
+	}
+
+	for _, pattern := range strings.Split(properties.Exempt, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern != "" {
+			interceptor.exempts = append(interceptor.exempts, pattern)
+		}
+	}
+	return interceptor
+}
+
+func (interceptor CSRFInterceptor) WithRepository(repository CSRFTokenRepository) CSRFInterceptor {
+	if repository == nil {
+		panic("Repository must not be null")
+	}
+
+	interceptor.repository = repository
+	return interceptor
+}
+
+func (interceptor CSRFInterceptor) WithExempt(patterns ...string) CSRFInterceptor {
+	exempts := make([]string, len(interceptor.exempts), len(interceptor.exempts)+len(patterns))
+	copy(exempts, interceptor.exempts)
+	interceptor.exempts = append(exempts, patterns...)
+	return interceptor
+}
+
+func (interceptor CSRFInterceptor) GetPriority() core.PriorityValue {
+	return CSRFInterceptorPriority
+}
+
+func (interceptor CSRFInterceptor) HandleBefore(ctx *WebRequestContext) {
+	if !interceptor.enabled {
+		return
+	}
+
+	ctx.Put(csrfStateKey, &csrfState{
+		repository: interceptor.repository,
+	})
+
+	if isSafeMethod(ctx.fastHttpRequestContext.Method()) || interceptor.isExempt(ctx.GetPath()) {
+		return
+	}
+
+	expectedToken, ok := interceptor.repository.LoadToken(ctx)
+	actualToken := interceptor.getRequestToken(ctx)
+	if !ok || expectedToken == "" || actualToken == "" ||
+		subtle.ConstantTimeCompare([]byte(expectedToken), []byte(actualToken)) != 1 {
+		ctx.SetHTTPError(HttpErrorForbidden.WithDetail(csrfInvalidToken))
+		ctx.Cancel()
+	}
+}
+
+func (interceptor CSRFInterceptor) getRequestToken(ctx *WebRequestContext) string {
+	if token, ok := ctx.GetRequestHeader(interceptor.headerName); ok && token != "" {
+		return token
+	}
+
+	request := &ctx.fastHttpRequestContext.Request
+	if token := request.PostArgs().Peek(interceptor.fieldName); len(token) != 0 {
+		return string(token)
+	}
+
+	if form, err := request.MultipartForm(); err == nil && form != nil {
+		if values := form.Value[interceptor.fieldName]; len(values) != 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+func (interceptor CSRFInterceptor) isExempt(requestPath string) bool {
+	for _, pattern := range interceptor.exempts {
+		if matchPathPattern(pattern, requestPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSafeMethod(method []byte) bool {
+	switch string(method) {
+	case fasthttp.MethodGet, fasthttp.MethodHead, fasthttp.MethodOptions, fasthttp.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
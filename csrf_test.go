@@ -0,0 +1,130 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type testCSRFController struct {
+}
+
+func (controller testCSRFController) RegisterHandlers(registry HandlerRegistry) {
+	registry.Register(
+		Get(controller.getForm, Path("/form")),
+		Post(controller.submitForm, Path("/form")),
+		Post(controller.submitForm, Path("/webhooks/github")),
+	)
+}
+
+func (controller testCSRFController) getForm(ctx *WebRequestContext) {
+	ctx.Ok().SetModel(ctx.GetCSRFToken())
+}
+
+func (controller testCSRFController) submitForm(ctx *WebRequestContext) {
+	ctx.Ok().SetModel("submitted")
+}
+
+func TestCSRFInterceptor_DoubleSubmit(t *testing.T) {
+	interceptor := NewCSRFInterceptor(&CSRFProperties{Enabled: true, Exempt: "/webhooks/**"})
+	router := NewRouter(WithControllers(testCSRFController{}), WithInterceptors(interceptor))
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/form", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	token := string(response.Body())
+	assert.NotEmpty(t, token)
+	cookie, ok := getTestResponseCookie(response, DefaultCSRFCookieName)
+	assert.True(t, ok)
+	assert.Equal(t, token, string(cookie.Value()))
+	assert.False(t, cookie.HTTPOnly())
+
+	requestCookie := DefaultCSRFCookieName + "=" + token
+	response = serveTestAdapterRequest(router, http.MethodPost, "/form", map[string]string{"Cookie": requestCookie, DefaultCSRFHeaderName: token}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodPost, "/form", map[string]string{
+		"Cookie":       requestCookie,
+		"Content-Type": "application/x-www-form-urlencoded",
+	}, DefaultCSRFFieldName+"="+token)
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodPost, "/form?_csrf="+token, map[string]string{"Cookie": requestCookie}, "")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodPost, "/form", map[string]string{"Cookie": requestCookie, DefaultCSRFHeaderName: "forged"}, "")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodPost, "/form", map[string]string{DefaultCSRFHeaderName: token}, "")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodPost, "/webhooks/github", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+}
+
+func TestCSRFInterceptor_Synchronizer(t *testing.T) {
+	interceptor := NewCSRFInterceptor(&CSRFProperties{
+		Enabled:    true,
+		Mode:       CSRFModeSynchronizer,
+		HeaderName: "X-CSRF-TOKEN",
+	})
+	router := NewRouter(WithControllers(testCSRFController{}), WithInterceptors(NewSessionInterceptor(nil), interceptor))
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/form", map[string]string{"Cookie": DefaultSessionCookieName + "=forged"}, "")
+	token := string(response.Body())
+	assert.NotEmpty(t, token)
+	_, ok := getTestResponseCookie(response, DefaultCSRFCookieName)
+	assert.False(t, ok)
+
+	cookie, ok := getTestResponseCookie(response, DefaultSessionCookieName)
+	assert.True(t, ok)
+	assert.NotEqual(t, "forged", string(cookie.Value()))
+	session := map[string]string{"Cookie": DefaultSessionCookieName + "=" + string(cookie.Value())}
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/form", session, "")
+	assert.Equal(t, token, string(response.Body()))
+
+	response = serveTestAdapterRequest(router, http.MethodPost, "/form", map[string]string{
+		"Cookie":       session["Cookie"],
+		"X-CSRF-TOKEN": token,
+	}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodPost, "/form", map[string]string{
+		"Cookie":       DefaultSessionCookieName + "=forged",
+		"X-CSRF-TOKEN": token,
+	}, "")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode())
+}
+
+func TestCSRFInterceptor_SessionRepository(t *testing.T) {
+	router := NewRouter(
+		WithControllers(testCSRFController{}),
+		WithInterceptors(
+			NewSessionInterceptor(nil),
+			NewCSRFInterceptor(nil).WithRepository(NewSessionCSRFTokenRepository()),
+		),
+	)
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/form", nil, "")
+	token := string(response.Body())
+	cookie, _ := getTestResponseCookie(response, DefaultSessionCookieName)
+	sessionCookie := DefaultSessionCookieName + "=" + string(cookie.Value())
+
+	response = serveTestAdapterRequest(router, http.MethodPost, "/form", map[string]string{"Cookie": sessionCookie, DefaultCSRFHeaderName: token}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodPost, "/form", map[string]string{DefaultCSRFHeaderName: token}, "")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode())
+}
+
+func TestCSRFInterceptor_Disabled(t *testing.T) {
+	interceptor := NewCSRFInterceptor(&CSRFProperties{})
+	router := NewRouter(WithControllers(testCSRFController{}), WithInterceptors(interceptor))
+
+	response := serveTestAdapterRequest(router, http.MethodPost, "/form", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+
+	assert.Panics(t, func() {
+		NewCSRFInterceptor(&CSRFProperties{Enabled: true, Mode: "unknown"})
+	})
+}
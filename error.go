@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	context "github.com/procyon-projects/procyon-context"
+	"gopkg.in/go-playground/validator.v9"
 	"net/http"
 	"runtime/debug"
 )
@@ -30,8 +31,13 @@ func (err *HTTPError) Error() string {
 }
 
 type HTTPError struct {
-	Code    int
-	Message interface{}
+	Code       int
+	Message    interface{}
+	Type       string                 `json:"-" xml:"-"`
+	Title      string                 `json:"-" xml:"-"`
+	Detail     string                 `json:"-" xml:"-"`
+	Instance   string                 `json:"-" xml:"-"`
+	Extensions map[string]interface{} `json:"-" xml:"-"`
 }
 
 func NewHTTPError(code int, message ...interface{}) *HTTPError {
@@ -47,37 +53,199 @@ func NewHTTPError(code int, message ...interface{}) *HTTPError {
 	return httpError
 }
 
+func (err *HTTPError) clone() *HTTPError {
+	httpError := *err
+	if err.Extensions != nil {
+		httpError.Extensions = make(map[string]interface{}, len(err.Extensions))
+		for key, value := range err.Extensions {
+			httpError.Extensions[key] = value
+		}
+	}
+	return &httpError
+}
+
+func (err *HTTPError) WithType(problemType string) *HTTPError {
+	httpError := err.clone()
+	httpError.Type = problemType
+	return httpError
+}
+
+func (err *HTTPError) WithTitle(title string) *HTTPError {
+	httpError := err.clone()
+	httpError.Title = title
+	return httpError
+}
+
+func (err *HTTPError) WithDetail(detail string) *HTTPError {
+	httpError := err.clone()
+	httpError.Detail = detail
+	return httpError
+}
+
+func (err *HTTPError) WithInstance(instance string) *HTTPError {
+	httpError := err.clone()
+	httpError.Instance = instance
+	return httpError
+}
+
+func (err *HTTPError) WithExtension(key string, value interface{}) *HTTPError {
+	httpError := err.clone()
+	if httpError.Extensions == nil {
+		httpError.Extensions = make(map[string]interface{})
+	}
+	httpError.Extensions[key] = value
+	return httpError
+}
+
 type ErrorHandler interface {
 	HandleError(err error, requestContext *WebRequestContext)
 }
 
 type DefaultErrorHandler struct {
-	logger context.Logger
+	logger                context.Logger
+	problemDetails        bool
+	validationErrorStatus int
 }
 
 func NewDefaultErrorHandler(logger context.Logger) DefaultErrorHandler {
 	return DefaultErrorHandler{
-		logger,
+		logger:                logger,
+		validationErrorStatus: http.StatusBadRequest,
+	}
+}
+
+func NewProblemDetailsErrorHandler(logger context.Logger) DefaultErrorHandler {
+	return DefaultErrorHandler{
+		logger:                logger,
+		problemDetails:        true,
+		validationErrorStatus: http.StatusBadRequest,
+	}
+}
+
+func (handler DefaultErrorHandler) WithValidationErrorStatus(status int) DefaultErrorHandler {
+	if status == http.StatusBadRequest || status == http.StatusUnprocessableEntity {
+		handler.validationErrorStatus = status
+	}
+	return handler
+}
+
+type validationErrorModel struct {
+	Code    int
+	Message string
+	Errors  []FieldError
+}
+
+func asValidationError(err error) (*ValidationError, bool) {
+	var validationError *ValidationError
+	if errors.As(err, &validationError) {
+		return validationError, true
+	}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return NewValidationError(validationErrors), true
 	}
+
+	return nil, false
 }
 
 func (handler DefaultErrorHandler) HandleError(err error, requestContext *WebRequestContext) {
+	if handler.problemDetails {
+		handler.handleProblemDetails(err, requestContext)
+		return
+	}
+
 	if httpError, ok := err.(*HTTPError); ok {
 		requestContext.SetResponseStatus(httpError.Code)
-		requestContext.SetModel(httpError)
+		requestContext.SetModel(localizeHTTPError(httpError, requestContext))
+	} else if validationError, ok := asValidationError(err); ok {
+		requestContext.SetResponseStatus(handler.validationErrorStatus)
+		requestContext.SetModel(&validationErrorModel{
+			Code:    handler.validationErrorStatus,
+			Message: localizeMessage(requestContext, validationFailedMessageCode, "Validation failed"),
+			Errors:  validationError.localize(requestContext).Errors,
+		})
 	} else {
 		handler.logger.Error(requestContext, err.Error()+"\n"+string(debug.Stack()))
 		requestContext.SetResponseStatus(HttpErrorInternalServerError.Code)
-		requestContext.SetModel(HttpErrorInternalServerError)
+		requestContext.SetModel(localizeHTTPError(HttpErrorInternalServerError, requestContext))
 	}
 
 	requestContext.SetResponseContentType(MediaTypeApplicationJson)
 }
 
+func (handler DefaultErrorHandler) handleProblemDetails(err error, requestContext *WebRequestContext) {
+	var problemDetail *ProblemDetail
+
+	var httpError *HTTPError
+	var bindingError *BindingError
+
+	if errors.As(err, &httpError) {
+		problemDetail = NewProblemDetail(httpError)
+	} else if validationError, ok := asValidationError(err); ok {
+		problemDetail = NewProblemDetail(NewHTTPError(handler.validationErrorStatus).WithDetail(localizeMessage(requestContext, validationFailedMessageCode, "Validation failed")))
+		problemDetail.Extensions["errors"] = validationError.localize(requestContext).toProblemErrors()
+	} else if errors.As(err, &bindingError) {
+		problemDetail = NewProblemDetail(HttpErrorBadRequest.WithDetail(localizeMessage(requestContext, bindingFailedMessageCode, "Request could not be bound")))
+		problemDetail.Extensions["errors"] = []ProblemError{bindingError.toProblemError()}
+	} else {
+		handler.logger.Error(requestContext, err.Error()+"\n"+string(debug.Stack()))
+		problemDetail = NewProblemDetail(HttpErrorInternalServerError)
+	}
+
+	if problemDetail.Title == http.StatusText(problemDetail.Status) {
+		problemDetail.Title = localizeMessage(requestContext, statusMessageCode(problemDetail.Status), problemDetail.Title)
+	} else {
+		problemDetail.Title = localizeMessage(requestContext, problemDetail.Title, problemDetail.Title)
+	}
+	problemDetail.Detail = localizeMessage(requestContext, problemDetail.Detail, problemDetail.Detail)
+
+	if problemDetail.Instance == "" && requestContext.fastHttpRequestContext != nil {
+		problemDetail.Instance = requestContext.GetPath()
+	}
+
+	requestContext.SetResponseStatus(problemDetail.Status)
+	requestContext.SetModel(problemDetail)
+	requestContext.SetResponseContentType(MediaTypeApplicationProblemJson)
+}
+
+func localizeMessage(ctx *WebRequestContext, code string, defaultMessage string, args ...string) string {
+	if code == "" {
+		return defaultMessage
+	}
+
+	if message, ok := ctx.GetMessage(code, args...); ok {
+		return message
+	}
+	return defaultMessage
+}
+
+func localizeHTTPError(httpError *HTTPError, ctx *WebRequestContext) *HTTPError {
+	message, ok := httpError.Message.(string)
+	if !ok {
+		return httpError
+	}
+
+	code := message
+	if message == http.StatusText(httpError.Code) {
+		code = statusMessageCode(httpError.Code)
+	}
+
+	localizedMessage, ok := ctx.GetMessage(code)
+	if !ok || localizedMessage == message {
+		return httpError
+	}
+
+	localizedError := httpError.clone()
+	localizedError.Message = localizedMessage
+	return localizedError
+}
+
 type errorHandlerManager struct {
-	defaultErrorHandler ErrorHandler
-	customErrorHandler  ErrorHandler
-	logger              context.Logger
+	defaultErrorHandler  ErrorHandler
+	customErrorHandler   ErrorHandler
+	errorHandlerRegistry ErrorHandlerRegistry
+	logger               context.Logger
 }
 
 func newErrorHandlerManager(logger context.Logger) *errorHandlerManager {
@@ -94,19 +262,41 @@ func (errorHandlerManager *errorHandlerManager) Recover(ctx *WebRequestContext)
 		case *HTTPError:
 			ctx.httpError = err
 			errorHandlerManager.HandleError(ctx.httpError, ctx)
-			return
 		case string:
 			ctx.internalError = errors.New(err)
+			errorHandlerManager.HandleError(ctx.internalError, ctx)
 		case error:
 			ctx.internalError = err
+			errorHandlerManager.HandleError(ctx.internalError, ctx)
 		default:
 			ctx.internalError = errors.New("unknown error : \n" + string(debug.Stack()))
+			errorHandlerManager.HandleError(ctx.internalError, ctx)
 		}
-		errorHandlerManager.HandleError(ctx.internalError, ctx)
+		ctx.invokeAfterCompletionHandlers()
 	}
 }
 
+func (errorHandlerManager *errorHandlerManager) handleMappedError(err error, ctx *WebRequestContext) bool {
+	if errorHandlerManager.errorHandlerRegistry == nil {
+		return false
+	}
+
+	errorHandler, matchedErr := errorHandlerManager.errorHandlerRegistry.FindErrorHandler(err, ctx)
+	if errorHandler == nil {
+		return false
+	}
+
+	errorHandler(matchedErr, ctx)
+	return true
+}
+
 func (errorHandlerManager *errorHandlerManager) JustHandleError(err error, ctx *WebRequestContext) {
+	ctx.traceError(err)
+
+	if errorHandlerManager.handleMappedError(err, ctx) {
+		return
+	}
+
 	if errorHandlerManager.customErrorHandler != nil {
 		errorHandlerManager.customErrorHandler.HandleError(err, ctx)
 	} else {
@@ -117,11 +307,7 @@ func (errorHandlerManager *errorHandlerManager) JustHandleError(err error, ctx *
 func (errorHandlerManager *errorHandlerManager) HandleError(err error, ctx *WebRequestContext) {
 	defer errorHandlerManager.wtf(err, ctx)
 
-	if errorHandlerManager.customErrorHandler != nil {
-		errorHandlerManager.customErrorHandler.HandleError(err, ctx)
-	} else {
-		errorHandlerManager.defaultErrorHandler.HandleError(err, ctx)
-	}
+	errorHandlerManager.JustHandleError(err, ctx)
 	ctx.writeResponse()
 
 	if ctx.handlerChain != nil && ctx.handlerIndex < ctx.handlerChain.handlerIndex {
@@ -0,0 +1,199 @@
+package web
+
+import (
+	"errors"
+	core "github.com/procyon-projects/procyon-core"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type ErrorHandlerFunction func(err error, requestContext *WebRequestContext)
+
+type ErrorHandlerOption func(mapping *ErrorHandlerMapping)
+
+type ErrorAdvice interface {
+	RegisterErrorHandlers(registry ErrorHandlerRegistry)
+}
+
+type ErrorHandlerMapping struct {
+	handler         ErrorHandlerFunction
+	match           func(err error) (error, bool)
+	priority        core.PriorityValue
+	pathPrefixes    []string
+	controllerTypes []reflect.Type
+}
+
+func newErrorHandlerMapping(match func(err error) (error, bool), handler ErrorHandlerFunction, options ...ErrorHandlerOption) ErrorHandlerMapping {
+	if handler == nil {
+		panic("Error handler must not be null")
+	}
+
+	mapping := &ErrorHandlerMapping{
+		handler:  handler,
+		match:    match,
+		priority: core.PriorityLowest,
+	}
+
+	for _, option := range options {
+		option(mapping)
+	}
+
+	return *mapping
+}
+
+func OnError(target error, handler ErrorHandlerFunction, options ...ErrorHandlerOption) ErrorHandlerMapping {
+	if target == nil {
+		panic("Target error must not be null")
+	}
+
+	return newErrorHandlerMapping(func(err error) (error, bool) {
+		return err, errors.Is(err, target)
+	}, handler, options...)
+}
+
+func OnErrorType(errorType interface{}, handler ErrorHandlerFunction, options ...ErrorHandlerOption) ErrorHandlerMapping {
+	typ := reflect.TypeOf(errorType)
+	if typ == nil {
+		panic("Error type must not be null")
+	}
+
+	if typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Interface {
+		typ = typ.Elem()
+	}
+
+	if !typ.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
+		panic("Error type must implement the error interface : " + typ.String())
+	}
+
+	return newErrorHandlerMapping(func(err error) (error, bool) {
+		target := reflect.New(typ)
+		if errors.As(err, target.Interface()) {
+			return target.Elem().Interface().(error), true
+		}
+		return nil, false
+	}, handler, options...)
+}
+
+func ErrorPriority(priority core.PriorityValue) ErrorHandlerOption {
+	return func(mapping *ErrorHandlerMapping) {
+		mapping.priority = priority
+	}
+}
+
+func ErrorPathPrefix(prefixes ...string) ErrorHandlerOption {
+	return func(mapping *ErrorHandlerMapping) {
+		mapping.pathPrefixes = append(mapping.pathPrefixes, prefixes...)
+	}
+}
+
+func ErrorControllers(controllers ...Controller) ErrorHandlerOption {
+	return func(mapping *ErrorHandlerMapping) {
+		for _, controller := range controllers {
+			if controller == nil {
+				continue
+			}
+			mapping.controllerTypes = append(mapping.controllerTypes, indirectType(reflect.TypeOf(controller)))
+		}
+	}
+}
+
+func ErrorResponse(status int, model interface{}) ErrorHandlerFunction {
+	return func(err error, requestContext *WebRequestContext) {
+		requestContext.SetResponseStatus(status)
+		requestContext.SetModel(model)
+		requestContext.SetResponseContentType(MediaTypeApplicationJson)
+	}
+}
+
+func (mapping ErrorHandlerMapping) appliesTo(ctx *WebRequestContext) bool {
+	if len(mapping.pathPrefixes) != 0 {
+		if ctx.fastHttpRequestContext == nil {
+			return false
+		}
+
+		path := ctx.GetPath()
+		matched := false
+		for _, prefix := range mapping.pathPrefixes {
+			if strings.HasPrefix(path, prefix) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	if len(mapping.controllerTypes) != 0 {
+		if ctx.handlerChain == nil || ctx.handlerChain.controller == nil {
+			return false
+		}
+
+		controllerType := indirectType(reflect.TypeOf(ctx.handlerChain.controller))
+		for _, typ := range mapping.controllerTypes {
+			if typ == controllerType {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+type ErrorHandlerRegistry interface {
+	Register(mappings ...ErrorHandlerMapping)
+	FindErrorHandler(err error, ctx *WebRequestContext) (ErrorHandlerFunction, error)
+}
+
+type SimpleErrorHandlerRegistry struct {
+	mappings []ErrorHandlerMapping
+	mu       sync.RWMutex
+}
+
+func NewSimpleErrorHandlerRegistry() *SimpleErrorHandlerRegistry {
+	return &SimpleErrorHandlerRegistry{
+		mappings: make([]ErrorHandlerMapping, 0),
+	}
+}
+
+func (registry *SimpleErrorHandlerRegistry) Register(mappings ...ErrorHandlerMapping) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.mappings = append(registry.mappings, mappings...)
+	sort.SliceStable(registry.mappings, func(i, j int) bool {
+		return registry.mappings[i].priority < registry.mappings[j].priority
+	})
+}
+
+func (registry *SimpleErrorHandlerRegistry) FindErrorHandler(err error, ctx *WebRequestContext) (ErrorHandlerFunction, error) {
+	if err == nil {
+		return nil, nil
+	}
+
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	for _, mapping := range registry.mappings {
+		if !mapping.appliesTo(ctx) {
+			continue
+		}
+
+		if matchedErr, ok := mapping.match(err); ok {
+			return mapping.handler, matchedErr
+		}
+	}
+
+	return nil, nil
+}
+
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
@@ -0,0 +1,95 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"github.com/procyon-projects/procyon-context"
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+var errTestOrderNotFound = errors.New("order not found")
+
+type testOrderError struct {
+	OrderId int
+}
+
+func (err *testOrderError) Error() string {
+	return fmt.Sprintf("order %d is invalid", err.OrderId)
+}
+
+type testOrderController struct {
+}
+
+func (controller testOrderController) RegisterHandlers(registry HandlerRegistry) {
+}
+
+type testErrorAdvice struct {
+}
+
+func (advice testErrorAdvice) RegisterErrorHandlers(registry ErrorHandlerRegistry) {
+	registry.Register(
+		OnError(errTestOrderNotFound, ErrorResponse(http.StatusNotFound, map[string]string{"message": "missing"})),
+		OnError(errTestOrderNotFound, ErrorResponse(http.StatusGone, map[string]string{"message": "gone"}),
+			ErrorPriority(core.PriorityHighest), ErrorPathPrefix("/archive")),
+		OnErrorType((*testOrderError)(nil), func(err error, requestContext *WebRequestContext) {
+			requestContext.SetResponseStatus(http.StatusUnprocessableEntity)
+			requestContext.SetModel(map[string]int{"orderId": err.(*testOrderError).OrderId})
+			requestContext.SetResponseContentType(MediaTypeApplicationJson)
+		}, ErrorControllers(&testOrderController{})),
+	)
+}
+
+func newTestErrorHandlerManager() *errorHandlerManager {
+	manager := newErrorHandlerManager(context.NewSimpleLogger())
+	registry := NewSimpleErrorHandlerRegistry()
+	_, _ = NewErrorAdviceProcessor(registry).BeforePeaInitialization("testErrorAdvice", testErrorAdvice{})
+	manager.errorHandlerRegistry = registry
+	return manager
+}
+
+func TestErrorHandlerRegistry_MatchesSentinelError(t *testing.T) {
+	manager := newTestErrorHandlerManager()
+	ctx := newTestErrorRequestContext("/orders/1")
+
+	manager.JustHandleError(fmt.Errorf("lookup failed: %w", errTestOrderNotFound), ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, http.StatusNotFound, ctx.fastHttpRequestContext.Response.StatusCode())
+	assert.Equal(t, "{\"message\":\"missing\"}", string(ctx.GetResponseBody()))
+}
+
+func TestErrorHandlerRegistry_UsesPriorityAndPathPrefix(t *testing.T) {
+	manager := newTestErrorHandlerManager()
+	ctx := newTestErrorRequestContext("/archive/orders/1")
+
+	manager.JustHandleError(errTestOrderNotFound, ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, http.StatusGone, ctx.fastHttpRequestContext.Response.StatusCode())
+	assert.Equal(t, "{\"message\":\"gone\"}", string(ctx.GetResponseBody()))
+}
+
+func TestErrorHandlerRegistry_MatchesErrorTypeForController(t *testing.T) {
+	manager := newTestErrorHandlerManager()
+	ctx := newTestErrorRequestContext("/orders/7")
+	ctx.handlerChain = &HandlerChain{controller: testOrderController{}}
+
+	manager.JustHandleError(fmt.Errorf("validation: %w", &testOrderError{OrderId: 7}), ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, http.StatusUnprocessableEntity, ctx.fastHttpRequestContext.Response.StatusCode())
+	assert.Equal(t, "{\"orderId\":7}", string(ctx.GetResponseBody()))
+}
+
+func TestErrorHandlerRegistry_FallsBackToDefaultErrorHandler(t *testing.T) {
+	manager := newTestErrorHandlerManager()
+	ctx := newTestErrorRequestContext("/orders/7")
+
+	manager.JustHandleError(&testOrderError{OrderId: 7}, ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, http.StatusInternalServerError, ctx.fastHttpRequestContext.Response.StatusCode())
+}
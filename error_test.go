@@ -0,0 +1,140 @@
+package web
+
+import (
+	"errors"
+	"github.com/procyon-projects/procyon-context"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"testing"
+)
+
+func newTestErrorRequestContext(path string) *WebRequestContext {
+	ctx := &WebRequestContext{
+		router: &ProcyonRouter{
+			responseBodyWriter: newDefaultResponseBodyWriter(),
+		},
+	}
+	ctx.fastHttpRequestContext = &fasthttp.RequestCtx{}
+	ctx.fastHttpRequestContext.Request.SetRequestURI(path)
+	ctx.prepare(true)
+	return ctx
+}
+
+func TestDefaultErrorHandler_HandleError(t *testing.T) {
+	handler := NewDefaultErrorHandler(context.NewSimpleLogger())
+	ctx := newTestErrorRequestContext("/orders/1")
+
+	handler.HandleError(HttpErrorNotFound.WithDetail("order not found"), ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, http.StatusNotFound, ctx.fastHttpRequestContext.Response.StatusCode())
+	assert.Equal(t, MediaTypeApplicationJsonValue, string(ctx.fastHttpRequestContext.Response.Header.ContentType()))
+	assert.Equal(t, "{\"Code\":404,\"Message\":\"Not Found\"}", string(ctx.GetResponseBody()))
+}
+
+func TestProblemDetailsErrorHandler_HandleHTTPError(t *testing.T) {
+	handler := NewProblemDetailsErrorHandler(context.NewSimpleLogger())
+	ctx := newTestErrorRequestContext("/orders/1")
+
+	httpError := HttpErrorNotFound.
+		WithType("https://procyon.dev/problems/order-not-found").
+		WithDetail("order 1 does not exist").
+		WithExtension("orderId", 1)
+	handler.HandleError(httpError, ctx)
+	ctx.writeResponse()
+
+	assert.Nil(t, HttpErrorNotFound.Extensions)
+	assert.Equal(t, http.StatusNotFound, ctx.fastHttpRequestContext.Response.StatusCode())
+	assert.Equal(t, MediaTypeApplicationProblemJsonValue, string(ctx.fastHttpRequestContext.Response.Header.ContentType()))
+	assert.Equal(t, "{\"type\":\"https://procyon.dev/problems/order-not-found\",\"title\":\"Not Found\","+
+		"\"status\":404,\"detail\":\"order 1 does not exist\",\"instance\":\"/orders/1\",\"orderId\":1}", string(ctx.GetResponseBody()))
+}
+
+func TestProblemDetailsErrorHandler_HandleMessage(t *testing.T) {
+	handler := NewProblemDetailsErrorHandler(context.NewSimpleLogger())
+	ctx := newTestErrorRequestContext("/orders")
+
+	handler.HandleError(NewHTTPError(http.StatusConflict, "order already exists").WithInstance("/orders/5"), ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, "{\"type\":\"about:blank\",\"title\":\"Conflict\",\"status\":409,"+
+		"\"detail\":\"order already exists\",\"instance\":\"/orders/5\"}", string(ctx.GetResponseBody()))
+}
+
+type testProblemRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Count int    `json:"count" validate:"min=3"`
+}
+
+func TestProblemDetailsErrorHandler_HandleValidationError(t *testing.T) {
+	handler := NewProblemDetailsErrorHandler(context.NewSimpleLogger())
+	ctx := newTestErrorRequestContext("/orders")
+
+	err := newDefaultValidator(nil).Validate(testProblemRequest{Count: 1})
+	handler.WithValidationErrorStatus(http.StatusUnprocessableEntity).HandleError(err, ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, http.StatusUnprocessableEntity, ctx.fastHttpRequestContext.Response.StatusCode())
+	assert.Equal(t, "{\"type\":\"about:blank\",\"title\":\"Unprocessable Entity\",\"status\":422,\"detail\":\"Validation failed\","+
+		"\"instance\":\"/orders\",\"errors\":[{\"field\":\"name\",\"rule\":\"required\",\"detail\":\"name is required\"},"+
+		"{\"field\":\"count\",\"rule\":\"min\",\"param\":\"3\",\"detail\":\"count must be at least 3\"}]}", string(ctx.GetResponseBody()))
+}
+
+type testNestedValidationRequest struct {
+	Body struct {
+		Name string `validate:"required"`
+	}
+	Order struct {
+		Body struct {
+			Text string `json:"text" validate:"required"`
+		} `json:"body"`
+	} `request:"body"`
+}
+
+func TestNewValidationError_FieldPath(t *testing.T) {
+	err := newDefaultValidator(nil).Validate(testNestedValidationRequest{})
+
+	validationError, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Len(t, validationError.Errors, 2)
+	assert.Equal(t, "Body.Name", validationError.Errors[0].Field)
+	assert.Equal(t, "body.text", validationError.Errors[1].Field)
+}
+
+func TestDefaultErrorHandler_HandleValidationError(t *testing.T) {
+	handler := NewDefaultErrorHandler(context.NewSimpleLogger())
+	ctx := newTestErrorRequestContext("/orders")
+
+	handler.HandleError(validator.New().Struct(testProblemRequest{Name: "order", Count: 1}), ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, http.StatusBadRequest, ctx.fastHttpRequestContext.Response.StatusCode())
+	assert.Equal(t, "{\"Code\":400,\"Message\":\"Validation failed\",\"Errors\":[{\"field\":\"Count\",\"rule\":\"min\","+
+		"\"param\":\"3\",\"message\":\"Count must be at least 3\"}]}", string(ctx.GetResponseBody()))
+}
+
+func TestProblemDetailsErrorHandler_HandleBindingError(t *testing.T) {
+	handler := NewProblemDetailsErrorHandler(context.NewSimpleLogger())
+	ctx := newTestErrorRequestContext("/orders")
+
+	handler.HandleError(newBindingError(BindingSourceParam, "page", errors.New("invalid syntax")), ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, http.StatusBadRequest, ctx.fastHttpRequestContext.Response.StatusCode())
+	assert.Equal(t, "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"Request could not be bound\","+
+		"\"instance\":\"/orders\",\"errors\":[{\"source\":\"param\",\"field\":\"page\",\"detail\":\"invalid syntax\"}]}", string(ctx.GetResponseBody()))
+}
+
+func TestProblemDetailsErrorHandler_HandleInternalError(t *testing.T) {
+	handler := NewProblemDetailsErrorHandler(context.NewSimpleLogger())
+	ctx := newTestErrorRequestContext("/orders")
+
+	handler.HandleError(errors.New("database is down"), ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, http.StatusInternalServerError, ctx.fastHttpRequestContext.Response.StatusCode())
+	assert.Equal(t, "{\"type\":\"about:blank\",\"title\":\"Internal Server Error\",\"status\":500,"+
+		"\"instance\":\"/orders\"}", string(ctx.GetResponseBody()))
+}
@@ -3,9 +3,10 @@ module github.com/procyon-projects/procyon-web
 go 1.15
 
 require (
-	github.com/go-playground/validator v9.31.0+incompatible
+	github.com/go-playground/locales v0.13.0
+	github.com/go-playground/universal-translator v0.17.0
 	github.com/google/uuid v1.2.0
-	github.com/json-iterator/go v1.1.11
+	github.com/json-iterator/go v1.1.12
 	github.com/procyon-projects/goo v1.0.4
 	github.com/procyon-projects/procyon-configure v0.1.0
 	github.com/procyon-projects/procyon-context v0.1.0
@@ -13,4 +14,5 @@ require (
 	github.com/procyon-projects/procyon-peas v0.1.0
 	github.com/stretchr/testify v1.6.1
 	github.com/valyala/fasthttp v1.26.0
+	gopkg.in/go-playground/validator.v9 v9.31.0
 )
@@ -1,8 +1,14 @@
 package web
 
+import "sync/atomic"
+
 type HandlerFunction func(requestContext *WebRequestContext)
 
 type HandlerChain struct {
+	method                    RequestMethod
+	pattern                   string
+	controller                Controller
+	validateRequest           bool
 	handler                   RequestHandlerFunction
 	handlers                  []HandlerFunction
 	handlerIndex              int
@@ -11,10 +17,16 @@ type HandlerChain struct {
 	handlerEndIndex           int
 	pathVariables             []string
 	requestObjectMetadata     *RequestObjectMetadata
+	securityRequirements      []SecurityRequirement
+	securityRequirementCache  atomic.Value
 }
 
 func NewHandlerChain(fun RequestHandlerFunction, interceptorRegistry HandlerInterceptorRegistry, metadata *RequestObjectMetadata) *HandlerChain {
 	chain := &HandlerChain{
+		"",
+		"",
+		nil,
+		false,
 		fun,
 		make([]HandlerFunction, 0),
 		0,
@@ -23,6 +35,8 @@ func NewHandlerChain(fun RequestHandlerFunction, interceptorRegistry HandlerInte
 		0,
 		nil,
 		metadata,
+		nil,
+		atomic.Value{},
 	}
 
 	if interceptorRegistry != nil {
@@ -58,6 +72,14 @@ func NewHandlerChain(fun RequestHandlerFunction, interceptorRegistry HandlerInte
 	return chain
 }
 
+func (chain *HandlerChain) GetMethod() RequestMethod {
+	return chain.method
+}
+
+func (chain *HandlerChain) GetPattern() string {
+	return chain.pattern
+}
+
 func (chain *HandlerChain) updatePathVariableMetadata(pathVariableIndex int, pathVariableName string) {
 	if chain.requestObjectMetadata == nil {
 		return
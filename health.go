@@ -0,0 +1,280 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+type HealthStatus string
+
+const (
+	HealthStatusUp           HealthStatus = "UP"
+	HealthStatusDown         HealthStatus = "DOWN"
+	HealthStatusOutOfService HealthStatus = "OUT_OF_SERVICE"
+	HealthStatusUnknown      HealthStatus = "UNKNOWN"
+)
+
+type HealthGroup string
+
+const (
+	HealthGroupLiveness  HealthGroup = "liveness"
+	HealthGroupReadiness HealthGroup = "readiness"
+)
+
+const readinessStateIndicatorName = "readinessState"
+
+type Health struct {
+	Status     HealthStatus           `json:"status"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	Components map[string]Health      `json:"components,omitempty"`
+}
+
+func NewHealth(status HealthStatus) Health {
+	return Health{
+		Status: status,
+	}
+}
+
+func (health Health) WithDetail(key string, value interface{}) Health {
+	details := make(map[string]interface{}, len(health.Details)+1)
+	for detailKey, detailValue := range health.Details {
+		details[detailKey] = detailValue
+	}
+	details[key] = value
+	health.Details = details
+	return health
+}
+
+func (health Health) WithError(err error) Health {
+	if err == nil {
+		return health
+	}
+	return health.WithDetail("error", err.Error())
+}
+
+func (health Health) GetHTTPStatus() int {
+	if health.Status == HealthStatusDown || health.Status == HealthStatusOutOfService {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+type HealthIndicator interface {
+	GetName() string
+	CheckHealth() Health
+}
+
+type HealthGroupIndicator interface {
+	HealthIndicator
+	GetHealthGroups() []HealthGroup
+}
+
+type HealthIndicatorRegistry interface {
+	RegisterHealthIndicator(indicators ...HealthIndicator)
+	GetHealth() Health
+	GetGroupHealth(group HealthGroup) Health
+	SetReady(ready bool)
+	IsReady() bool
+}
+
+type SimpleHealthIndicatorRegistry struct {
+	indicators []HealthIndicator
+	ready      int32
+	mu         sync.RWMutex
+}
+
+func NewSimpleHealthIndicatorRegistry() *SimpleHealthIndicatorRegistry {
+	return &SimpleHealthIndicatorRegistry{
+		indicators: make([]HealthIndicator, 0),
+		ready:      1,
+	}
+}
+
+func (registry *SimpleHealthIndicatorRegistry) RegisterHealthIndicator(indicators ...HealthIndicator) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for _, indicator := range indicators {
+		if indicator == nil {
+			panic("Health indicator must not be null")
+		}
+		registry.indicators = append(registry.indicators, indicator)
+	}
+}
+
+func (registry *SimpleHealthIndicatorRegistry) SetReady(ready bool) {
+	if ready {
+		atomic.StoreInt32(&registry.ready, 1)
+	} else {
+		atomic.StoreInt32(&registry.ready, 0)
+	}
+}
+
+func (registry *SimpleHealthIndicatorRegistry) IsReady() bool {
+	return atomic.LoadInt32(&registry.ready) == 1
+}
+
+func (registry *SimpleHealthIndicatorRegistry) GetHealth() Health {
+	components := registry.checkIndicators(func(indicator HealthIndicator) bool {
+		return true
+	})
+	components[readinessStateIndicatorName] = registry.readinessState()
+	return aggregateHealth(components)
+}
+
+func (registry *SimpleHealthIndicatorRegistry) GetGroupHealth(group HealthGroup) Health {
+	components := registry.checkIndicators(func(indicator HealthIndicator) bool {
+		return isHealthGroupMember(indicator, group)
+	})
+
+	if group == HealthGroupReadiness {
+		components[readinessStateIndicatorName] = registry.readinessState()
+	}
+	return aggregateHealth(components)
+}
+
+func (registry *SimpleHealthIndicatorRegistry) checkIndicators(filter func(indicator HealthIndicator) bool) map[string]Health {
+	registry.mu.RLock()
+	indicators := make([]HealthIndicator, 0, len(registry.indicators))
+	for _, indicator := range registry.indicators {
+		if filter(indicator) {
+			indicators = append(indicators, indicator)
+		}
+	}
+	registry.mu.RUnlock()
+
+	components := make(map[string]Health, len(indicators)+1)
+	for _, indicator := range indicators {
+		components[indicator.GetName()] = checkHealth(indicator)
+	}
+	return components
+}
+
+func (registry *SimpleHealthIndicatorRegistry) readinessState() Health {
+	if registry.IsReady() {
+		return NewHealth(HealthStatusUp)
+	}
+	return NewHealth(HealthStatusDown)
+}
+
+func checkHealth(indicator HealthIndicator) (health Health) {
+	defer func() {
+		if r := recover(); r != nil {
+			health = NewHealth(HealthStatusDown).WithDetail("error", fmt.Sprint(r))
+		}
+	}()
+
+	health = indicator.CheckHealth()
+	if health.Status == "" {
+		health.Status = HealthStatusUnknown
+	}
+	return health
+}
+
+func isHealthGroupMember(indicator HealthIndicator, group HealthGroup) bool {
+	groupIndicator, ok := indicator.(HealthGroupIndicator)
+	if !ok {
+		return group == HealthGroupReadiness
+	}
+
+	for _, healthGroup := range groupIndicator.GetHealthGroups() {
+		if healthGroup == group {
+			return true
+		}
+	}
+	return false
+}
+
+func aggregateHealth(components map[string]Health) Health {
+	status := HealthStatusUp
+
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch components[name].Status {
+		case HealthStatusDown:
+			status = HealthStatusDown
+		case HealthStatusOutOfService:
+			if status != HealthStatusDown {
+				status = HealthStatusOutOfService
+			}
+		}
+	}
+
+	health := NewHealth(status)
+	if len(components) != 0 {
+		health.Components = components
+	}
+	return health
+}
+
+type HealthController struct {
+	registry   HealthIndicatorRegistry
+	properties *HealthProperties
+}
+
+func NewHealthController(registry HealthIndicatorRegistry, properties *HealthProperties) HealthController {
+	if registry == nil {
+		panic("Health indicator registry must not be null")
+	}
+
+	if properties == nil {
+		properties = &HealthProperties{
+			Enabled:     true,
+			ShowDetails: true,
+		}
+	}
+
+	return HealthController{
+		registry,
+		properties,
+	}
+}
+
+func (controller HealthController) RegisterManagementHandlers(registry HandlerRegistry) {
+	if !controller.properties.Enabled {
+		return
+	}
+
+	registry.Register(
+		Get(controller.health, Path(pathOrDefault(controller.properties.Path, "/health"))),
+		Get(controller.liveness, Path(pathOrDefault(controller.properties.LivenessPath, "/health/liveness"))),
+		Get(controller.readiness, Path(pathOrDefault(controller.properties.ReadinessPath, "/health/readiness"))),
+	)
+}
+
+func (controller HealthController) health(ctx *WebRequestContext) {
+	controller.writeHealth(ctx, controller.registry.GetHealth())
+}
+
+func (controller HealthController) liveness(ctx *WebRequestContext) {
+	controller.writeHealth(ctx, controller.registry.GetGroupHealth(HealthGroupLiveness))
+}
+
+func (controller HealthController) readiness(ctx *WebRequestContext) {
+	controller.writeHealth(ctx, controller.registry.GetGroupHealth(HealthGroupReadiness))
+}
+
+func (controller HealthController) writeHealth(ctx *WebRequestContext, health Health) {
+	if !controller.properties.ShowDetails {
+		health = NewHealth(health.Status)
+	}
+
+	ctx.SetResponseStatus(health.GetHTTPStatus()).
+		SetModel(health).
+		SetResponseContentType(MediaTypeApplicationJson)
+}
+
+func pathOrDefault(path string, defaultPath string) string {
+	if path == "" {
+		return defaultPath
+	}
+	return path
+}
@@ -0,0 +1,127 @@
+package web
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type testHealthIndicator struct {
+	name   string
+	health Health
+	groups []HealthGroup
+}
+
+func (indicator testHealthIndicator) GetName() string {
+	return indicator.name
+}
+
+func (indicator testHealthIndicator) CheckHealth() Health {
+	return indicator.health
+}
+
+type testGroupHealthIndicator struct {
+	testHealthIndicator
+}
+
+func (indicator testGroupHealthIndicator) GetHealthGroups() []HealthGroup {
+	return indicator.groups
+}
+
+type testPanickingHealthIndicator struct {
+}
+
+func (indicator testPanickingHealthIndicator) GetName() string {
+	return "broken"
+}
+
+func (indicator testPanickingHealthIndicator) CheckHealth() Health {
+	panic("connection refused")
+}
+
+func TestSimpleHealthIndicatorRegistry_GetHealth(t *testing.T) {
+	registry := NewSimpleHealthIndicatorRegistry()
+	registry.RegisterHealthIndicator(
+		testHealthIndicator{name: "db", health: NewHealth(HealthStatusUp).WithDetail("database", "postgres")},
+		testHealthIndicator{name: "cache", health: NewHealth(HealthStatusOutOfService)},
+	)
+
+	health := registry.GetHealth()
+	assert.Equal(t, HealthStatusOutOfService, health.Status)
+	assert.Equal(t, http.StatusServiceUnavailable, health.GetHTTPStatus())
+	assert.Equal(t, "postgres", health.Components["db"].Details["database"])
+	assert.Equal(t, HealthStatusUp, health.Components[readinessStateIndicatorName].Status)
+
+	registry.RegisterHealthIndicator(testHealthIndicator{name: "queue", health: NewHealth(HealthStatusDown).WithError(errors.New("timeout"))})
+	health = registry.GetHealth()
+	assert.Equal(t, HealthStatusDown, health.Status)
+	assert.Equal(t, "timeout", health.Components["queue"].Details["error"])
+
+	registry = NewSimpleHealthIndicatorRegistry()
+	registry.RegisterHealthIndicator(testPanickingHealthIndicator{})
+	health = registry.GetHealth()
+	assert.Equal(t, HealthStatusDown, health.Status)
+	assert.Equal(t, "connection refused", health.Components["broken"].Details["error"])
+}
+
+func TestSimpleHealthIndicatorRegistry_GetGroupHealth(t *testing.T) {
+	registry := NewSimpleHealthIndicatorRegistry()
+	registry.RegisterHealthIndicator(
+		testHealthIndicator{name: "db", health: NewHealth(HealthStatusDown)},
+		testGroupHealthIndicator{testHealthIndicator{name: "deadlock", health: NewHealth(HealthStatusUp), groups: []HealthGroup{HealthGroupLiveness}}},
+	)
+
+	liveness := registry.GetGroupHealth(HealthGroupLiveness)
+	assert.Equal(t, HealthStatusUp, liveness.Status)
+	assert.Len(t, liveness.Components, 1)
+	assert.Contains(t, liveness.Components, "deadlock")
+
+	readiness := registry.GetGroupHealth(HealthGroupReadiness)
+	assert.Equal(t, HealthStatusDown, readiness.Status)
+	assert.Len(t, readiness.Components, 2)
+	assert.Contains(t, readiness.Components, "db")
+	assert.Contains(t, readiness.Components, readinessStateIndicatorName)
+}
+
+func TestHealthController(t *testing.T) {
+	registry := NewSimpleHealthIndicatorRegistry()
+	registry.RegisterHealthIndicator(testHealthIndicator{name: "db", health: NewHealth(HealthStatusUp)})
+	router := NewRouter(WithManagementControllers(NewHealthController(registry, nil)))
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/health", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.Equal(t, MediaTypeApplicationJsonValue, string(response.Header.ContentType()))
+	assert.JSONEq(t, "{\"status\":\"UP\",\"components\":{\"db\":{\"status\":\"UP\"},\"readinessState\":{\"status\":\"UP\"}}}", string(response.Body()))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/health/liveness", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.JSONEq(t, "{\"status\":\"UP\"}", string(response.Body()))
+
+	server := &ProcyonWebServer{healthIndicatorRegistry: registry}
+	assert.Nil(t, server.Stop())
+	assert.False(t, registry.IsReady())
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/health/readiness", nil, "")
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode())
+	assert.JSONEq(t, "{\"status\":\"DOWN\",\"components\":{\"db\":{\"status\":\"UP\"},\"readinessState\":{\"status\":\"DOWN\"}}}", string(response.Body()))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/health/liveness", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+}
+
+func TestHealthController_WithoutDetails(t *testing.T) {
+	registry := NewSimpleHealthIndicatorRegistry()
+	router := NewRouter(WithManagementControllers(NewHealthController(registry, &HealthProperties{
+		Enabled:       true,
+		Path:          "/status",
+		ReadinessPath: "/status/ready",
+	})))
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/status", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.JSONEq(t, "{\"status\":\"UP\"}", string(response.Body()))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/status/ready", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+}
@@ -12,4 +12,58 @@ func init() {
 	/* Handler Interceptor Registry & Processor */
 	core.Register(NewSimpleHandlerInterceptorRegistry)
 	core.Register(NewHandlerInterceptorProcessor)
+	/* Error Handler Registry & Error Advice Processor */
+	core.Register(NewSimpleErrorHandlerRegistry)
+	core.Register(NewErrorAdviceProcessor)
+	/* Health Indicator Registry, Processor & Controller */
+	core.Register(NewSimpleHealthIndicatorRegistry)
+	core.Register(NewHealthIndicatorProcessor)
+	core.Register(NewHealthController)
+	/* Management Registry & Processor */
+	core.Register(NewSimpleManagementRegistry)
+	core.Register(NewManagementProcessor)
+	/* Metrics, Interceptor & Controller */
+	core.Register(NewHTTPMetrics)
+	core.Register(NewMetricsInterceptor)
+	core.Register(NewMetricsController)
+	/* Access Log Interceptor */
+	core.Register(NewAccessLogInterceptor)
+	/* Authenticator Registry, Processor & Authentication Interceptor */
+	core.Register(NewSimpleAuthenticatorRegistry)
+	core.Register(NewAuthenticatorProcessor)
+	core.Register(NewAuthenticationInterceptor)
+	core.Register(newJWTAuthenticator)
+	/* Security Rule Registry, Processor & Routes Controller */
+	core.Register(NewSimpleSecurityRuleRegistry)
+	core.Register(NewSecurityConfigurerProcessor)
+	core.Register(NewRoutesController)
+	/* Rate Limit */
+	core.Register(NewRateLimitInterceptor)
+	/* Session & CSRF */
+	core.Register(NewSessionInterceptor)
+	core.Register(NewCSRFInterceptor)
+	/* Security Headers */
+	core.Register(NewSecurityHeadersInterceptor)
+	/* IP Filter Rule Set & Controller */
+	core.Register(NewIPFilterRuleSet)
+	core.Register(NewIPFilterController)
+	/* Properties */
+	core.Register(newErrorProperties)
+	core.Register(newLocaleProperties)
+	core.Register(newServerBackendProperties)
+	core.Register(newHealthProperties)
+	core.Register(newShutdownProperties)
+	core.Register(newManagementServerProperties)
+	core.Register(newMetricsProperties)
+	core.Register(newTracingProperties)
+	core.Register(newRequestIdProperties)
+	core.Register(newAccessLogProperties)
+	core.Register(newForwardedProperties)
+	core.Register(newRateLimitProperties)
+	core.Register(newAuthenticationProperties)
+	core.Register(newJWTProperties)
+	core.Register(newSessionProperties)
+	core.Register(newCSRFProperties)
+	core.Register(newSecurityHeadersProperties)
+	core.Register(newIPFilterProperties)
 }
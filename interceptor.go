@@ -16,6 +16,10 @@ type HandlerInterceptorAfterCompletion interface {
 	AfterCompletion(requestContext *WebRequestContext)
 }
 
+type UnmatchedRequestInterceptor interface {
+	InterceptsUnmatchedRequests() bool
+}
+
 type handlerInterceptorData struct {
 	interceptorFunction HandlerInterceptor
 	priority            core.PriorityValue
@@ -36,16 +40,18 @@ type HandlerInterceptorRegistry interface {
 }
 
 type SimpleHandlerInterceptorRegistry struct {
-	beforeInterceptors          []*handlerInterceptorData
-	afterInterceptors           []*handlerInterceptorData
-	afterCompletionInterceptors []*handlerInterceptorData
+	beforeInterceptors           []*handlerInterceptorData
+	afterInterceptors            []*handlerInterceptorData
+	afterCompletionInterceptors  []*handlerInterceptorData
+	unmatchedRequestInterceptors []interface{}
 }
 
 func NewSimpleHandlerInterceptorRegistry() *SimpleHandlerInterceptorRegistry {
 	return &SimpleHandlerInterceptorRegistry{
-		beforeInterceptors:          make([]*handlerInterceptorData, 0),
-		afterInterceptors:           make([]*handlerInterceptorData, 0),
-		afterCompletionInterceptors: make([]*handlerInterceptorData, 0),
+		beforeInterceptors:           make([]*handlerInterceptorData, 0),
+		afterInterceptors:            make([]*handlerInterceptorData, 0),
+		afterCompletionInterceptors:  make([]*handlerInterceptorData, 0),
+		unmatchedRequestInterceptors: make([]interface{}, 0),
 	}
 }
 
@@ -66,14 +72,19 @@ func (registry *SimpleHandlerInterceptorRegistry) RegisterHandlerInterceptor(int
 	if interceptor, ok := interceptor.(HandlerInterceptorAfterCompletion); ok {
 		registry.registerHandlerInterceptorAfterCompletion(priority, interceptor.AfterCompletion)
 	}
+
+	if unmatchedRequestInterceptor, ok := interceptor.(UnmatchedRequestInterceptor); ok && unmatchedRequestInterceptor.InterceptsUnmatchedRequests() {
+		registry.unmatchedRequestInterceptors = append(registry.unmatchedRequestInterceptors, interceptor)
+	}
 }
 
 func (registry *SimpleHandlerInterceptorRegistry) registerHandlerInterceptorBefore(priority core.PriorityValue,
 	interceptor HandlerInterceptor) {
-	interceptorIndex := 0
+	interceptorIndex := len(registry.beforeInterceptors)
 	for index, registeredInterceptor := range registry.beforeInterceptors {
 		if registeredInterceptor.priority > priority {
 			interceptorIndex = index
+			break
 		}
 	}
 
@@ -84,10 +95,11 @@ func (registry *SimpleHandlerInterceptorRegistry) registerHandlerInterceptorBefo
 
 func (registry *SimpleHandlerInterceptorRegistry) registerHandlerInterceptorAfter(priority core.PriorityValue,
 	interceptor HandlerInterceptor) {
-	interceptorIndex := 0
+	interceptorIndex := len(registry.afterInterceptors)
 	for index, registeredInterceptor := range registry.afterInterceptors {
-		if registeredInterceptor.priority < priority {
+		if registeredInterceptor.priority <= priority {
 			interceptorIndex = index
+			break
 		}
 	}
 
@@ -98,10 +110,11 @@ func (registry *SimpleHandlerInterceptorRegistry) registerHandlerInterceptorAfte
 
 func (registry *SimpleHandlerInterceptorRegistry) registerHandlerInterceptorAfterCompletion(priority core.PriorityValue,
 	interceptor HandlerInterceptor) {
-	interceptorIndex := 0
+	interceptorIndex := len(registry.afterCompletionInterceptors)
 	for index, registeredInterceptor := range registry.afterCompletionInterceptors {
-		if registeredInterceptor.priority < priority {
+		if registeredInterceptor.priority <= priority {
 			interceptorIndex = index
+			break
 		}
 	}
 
@@ -133,3 +146,20 @@ func (registry *SimpleHandlerInterceptorRegistry) GetHandlerAfterCompletionInter
 	}
 	return interceptors
 }
+
+func (registry *SimpleHandlerInterceptorRegistry) clone() *SimpleHandlerInterceptorRegistry {
+	return &SimpleHandlerInterceptorRegistry{
+		beforeInterceptors:           append(make([]*handlerInterceptorData, 0, len(registry.beforeInterceptors)), registry.beforeInterceptors...),
+		afterInterceptors:            append(make([]*handlerInterceptorData, 0, len(registry.afterInterceptors)), registry.afterInterceptors...),
+		afterCompletionInterceptors:  append(make([]*handlerInterceptorData, 0, len(registry.afterCompletionInterceptors)), registry.afterCompletionInterceptors...),
+		unmatchedRequestInterceptors: append(make([]interface{}, 0, len(registry.unmatchedRequestInterceptors)), registry.unmatchedRequestInterceptors...),
+	}
+}
+
+func (registry *SimpleHandlerInterceptorRegistry) getUnmatchedRequestInterceptorRegistry() *SimpleHandlerInterceptorRegistry {
+	unmatchedRequestRegistry := NewSimpleHandlerInterceptorRegistry()
+	for _, interceptor := range registry.unmatchedRequestInterceptors {
+		unmatchedRequestRegistry.RegisterHandlerInterceptor(interceptor)
+	}
+	return unmatchedRequestRegistry
+}
@@ -44,3 +44,76 @@ func TestHandlerInterceptorRegistry_RegisterHandlerInterceptor(t *testing.T) {
 	assert.Len(t, registry.afterInterceptors, 1)
 	assert.Len(t, registry.afterCompletionInterceptors, 1)
 }
+
+type testPriorityInterceptor struct {
+	name     string
+	priority core.PriorityValue
+	calls    *[]string
+}
+
+func (interceptor testPriorityInterceptor) HandleBefore(requestContext *WebRequestContext) {
+	*interceptor.calls = append(*interceptor.calls, "before:"+interceptor.name)
+}
+
+func (interceptor testPriorityInterceptor) HandleAfter(requestContext *WebRequestContext) {
+	*interceptor.calls = append(*interceptor.calls, "after:"+interceptor.name)
+}
+
+func (interceptor testPriorityInterceptor) AfterCompletion(requestContext *WebRequestContext) {
+	*interceptor.calls = append(*interceptor.calls, "afterCompletion:"+interceptor.name)
+}
+
+func (interceptor testPriorityInterceptor) GetPriority() core.PriorityValue {
+	return interceptor.priority
+}
+
+func TestHandlerInterceptorRegistry_OrdersByPriority(t *testing.T) {
+	calls := make([]string, 0)
+	registry := NewSimpleHandlerInterceptorRegistry()
+	registry.RegisterHandlerInterceptor(testPriorityInterceptor{"second", 20, &calls})
+	registry.RegisterHandlerInterceptor(testPriorityInterceptor{"third", core.PriorityLowest, &calls})
+	registry.RegisterHandlerInterceptor(testPriorityInterceptor{"first", 10, &calls})
+	registry.RegisterHandlerInterceptor(testPriorityInterceptor{"fourth", core.PriorityLowest, &calls})
+
+	for _, interceptor := range registry.GetHandlerBeforeInterceptors() {
+		interceptor(nil)
+	}
+	for _, interceptor := range registry.GetHandlerAfterInterceptors() {
+		interceptor(nil)
+	}
+	for _, interceptor := range registry.GetHandlerAfterCompletionInterceptors() {
+		interceptor(nil)
+	}
+
+	assert.Equal(t, []string{
+		"before:first", "before:second", "before:third", "before:fourth",
+		"after:fourth", "after:third", "after:second", "after:first",
+		"afterCompletion:fourth", "afterCompletion:third", "afterCompletion:second", "afterCompletion:first",
+	}, calls)
+}
+
+func TestHandlerInterceptorRegistry_ReversesAfterInterceptorOrder(t *testing.T) {
+	calls := make([]string, 0)
+	registry := NewSimpleHandlerInterceptorRegistry()
+	registry.RegisterHandlerInterceptor(testPriorityInterceptor{"10", 10, &calls})
+	registry.RegisterHandlerInterceptor(testPriorityInterceptor{"20", 20, &calls})
+	registry.RegisterHandlerInterceptor(testPriorityInterceptor{"5", 5, &calls})
+
+	for _, interceptor := range registry.GetHandlerBeforeInterceptors() {
+		interceptor(nil)
+	}
+	for _, interceptor := range registry.GetHandlerAfterInterceptors() {
+		interceptor(nil)
+	}
+	for _, interceptor := range registry.GetHandlerAfterCompletionInterceptors() {
+		interceptor(nil)
+	}
+
+	// interceptors used to be inserted at the last registered one with a greater (before) or smaller
+	// (after) priority value, which gave "before:20", "before:5", "before:10" and "after:5", "after:20", "after:10"
+	assert.Equal(t, []string{
+		"before:5", "before:10", "before:20",
+		"after:20", "after:10", "after:5",
+		"afterCompletion:20", "afterCompletion:10", "afterCompletion:5",
+	}, calls)
+}
@@ -0,0 +1,304 @@
+package web
+
+import (
+	"errors"
+	json "github.com/json-iterator/go"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrEmptyIPFilterPattern = errors.New("IP filter rule pattern must not be empty")
+
+type IPFilterRule struct {
+	Pattern string
+	Allow   []*net.IPNet
+	Deny    []*net.IPNet
+}
+
+func NewIPFilterRule(pattern string) IPFilterRule {
+	if pattern == "" {
+		panic("IP filter rule pattern must not be empty")
+	}
+
+	return IPFilterRule{
+		Pattern: pattern,
+		Allow:   make([]*net.IPNet, 0),
+		Deny:    make([]*net.IPNet, 0),
+	}
+}
+
+func (rule IPFilterRule) WithAllow(networks ...string) IPFilterRule {
+	rule.Allow = appendNetworks(rule.Allow, networks)
+	return rule
+}
+
+func (rule IPFilterRule) WithDeny(networks ...string) IPFilterRule {
+	rule.Deny = appendNetworks(rule.Deny, networks)
+	return rule
+}
+
+func appendNetworks(existing []*net.IPNet, values []string) []*net.IPNet {
+	networks, err := parseNetworks(values...)
+	if err != nil {
+		panic("Invalid network : " + err.Error())
+	}
+
+	result := make([]*net.IPNet, 0, len(existing)+len(networks))
+	result = append(result, existing...)
+	return append(result, networks...)
+}
+
+func (rule IPFilterRule) IsAllowed(ip net.IP) bool {
+	if ip == nil {
+		return len(rule.Allow) == 0 && len(rule.Deny) == 0
+	}
+
+	if containsIP(rule.Deny, ip) {
+		return false
+	}
+	return len(rule.Allow) == 0 || containsIP(rule.Allow, ip)
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+type ipFilterRuleDefinition struct {
+	Pattern string   `json:"pattern"`
+	Allow   []string `json:"allow"`
+	Deny    []string `json:"deny"`
+}
+
+type ipFilterRulesDefinition struct {
+	Rules []ipFilterRuleDefinition `json:"rules"`
+}
+
+func ParseIPFilterRules(data []byte) ([]IPFilterRule, error) {
+	definition := ipFilterRulesDefinition{}
+	if err := json.Unmarshal(data, &definition); err != nil {
+		return nil, err
+	}
+
+	rules := make([]IPFilterRule, 0, len(definition.Rules))
+	for _, ruleDefinition := range definition.Rules {
+		if ruleDefinition.Pattern == "" {
+			return nil, ErrEmptyIPFilterPattern
+		}
+
+		allow, err := parseNetworks(ruleDefinition.Allow...)
+		if err != nil {
+			return nil, err
+		}
+
+		deny, err := parseNetworks(ruleDefinition.Deny...)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, IPFilterRule{
+			Pattern: ruleDefinition.Pattern,
+			Allow:   allow,
+			Deny:    deny,
+		})
+	}
+	return rules, nil
+}
+
+func LoadIPFilterRulesFile(path string) ([]IPFilterRule, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseIPFilterRules(data)
+}
+
+type IPFilterRuleSet struct {
+	rules          atomic.Value
+	rulesFile      string
+	reloadInterval time.Duration
+	lastCheck      int64
+	fileModTime    time.Time
+	fileSize       int64
+	mu             sync.Mutex
+}
+
+func NewIPFilterRuleSet(properties *IPFilterProperties) *IPFilterRuleSet {
+	ruleSet := &IPFilterRuleSet{
+		lastCheck: time.Now().UnixNano(),
+	}
+	ruleSet.rules.Store(make([]IPFilterRule, 0))
+
+	if properties == nil || properties.RulesFile == "" {
+		return ruleSet
+	}
+
+	ruleSet.rulesFile = properties.RulesFile
+	ruleSet.reloadInterval = time.Duration(properties.ReloadInterval) * time.Second
+	if err := ruleSet.Reload(); err != nil {
+		panic("IP filter rules could not be loaded : " + err.Error())
+	}
+	return ruleSet
+}
+
+func (ruleSet *IPFilterRuleSet) SetRules(rules ...IPFilterRule) {
+	copiedRules := make([]IPFilterRule, len(rules))
+	copy(copiedRules, rules)
+	ruleSet.rules.Store(copiedRules)
+}
+
+func (ruleSet *IPFilterRuleSet) GetRules() []IPFilterRule {
+	return ruleSet.rules.Load().([]IPFilterRule)
+}
+
+func (ruleSet *IPFilterRuleSet) FindRule(requestPath string) (IPFilterRule, bool) {
+	for _, rule := range ruleSet.GetRules() {
+		if matchPathPattern(rule.Pattern, requestPath) {
+			return rule, true
+		}
+	}
+	return IPFilterRule{}, false
+}
+
+func (ruleSet *IPFilterRuleSet) IsAllowed(requestPath string, ip net.IP) bool {
+	rule, ok := ruleSet.FindRule(requestPath)
+	return !ok || rule.IsAllowed(ip)
+}
+
+func (ruleSet *IPFilterRuleSet) Reload() error {
+	if ruleSet.rulesFile == "" {
+		return nil
+	}
+
+	ruleSet.mu.Lock()
+	defer ruleSet.mu.Unlock()
+	return ruleSet.reload()
+}
+
+func (ruleSet *IPFilterRuleSet) reload() error {
+	info, err := os.Stat(ruleSet.rulesFile)
+	if err != nil {
+		return err
+	}
+
+	rules, err := LoadIPFilterRulesFile(ruleSet.rulesFile)
+	if err != nil {
+		return err
+	}
+
+	ruleSet.rules.Store(rules)
+	ruleSet.fileModTime = info.ModTime()
+	ruleSet.fileSize = info.Size()
+	return nil
+}
+
+func (ruleSet *IPFilterRuleSet) reloadIfChanged(now time.Time) error {
+	if ruleSet.rulesFile == "" || ruleSet.reloadInterval <= 0 {
+		return nil
+	}
+
+	lastCheck := atomic.LoadInt64(&ruleSet.lastCheck)
+	if now.UnixNano()-lastCheck < int64(ruleSet.reloadInterval) ||
+		!atomic.CompareAndSwapInt64(&ruleSet.lastCheck, lastCheck, now.UnixNano()) {
+		return nil
+	}
+
+	ruleSet.mu.Lock()
+	defer ruleSet.mu.Unlock()
+
+	info, err := os.Stat(ruleSet.rulesFile)
+	if err != nil {
+		return err
+	}
+
+	if info.ModTime().Equal(ruleSet.fileModTime) && info.Size() == ruleSet.fileSize {
+		return nil
+	}
+
+	ruleSet.fileModTime = info.ModTime()
+	ruleSet.fileSize = info.Size()
+	return ruleSet.reload()
+}
+
+func (router *ProcyonRouter) isAllowedByIPFilter(ctx *WebRequestContext) bool {
+	if router.ipFilterRuleSet == nil {
+		return true
+	}
+
+	if err := router.ipFilterRuleSet.reloadIfChanged(time.Now()); err != nil {
+		router.logger.Error(ctx, "IP filter rules could not be reloaded, keeping the previous rules : "+err.Error())
+	}
+	return router.ipFilterRuleSet.IsAllowed(ctx.GetPath(), ctx.ClientIP())
+}
+
+type IPFilterController struct {
+	ruleSet              *IPFilterRuleSet
+	properties           *IPFilterProperties
+	managementProperties *ManagementServerProperties
+}
+
+func NewIPFilterController(ruleSet *IPFilterRuleSet, properties *IPFilterProperties, managementProperties *ManagementServerProperties) IPFilterController {
+	if ruleSet == nil {
+		panic("IP filter rule set must not be null")
+	}
+
+	if properties == nil {
+		properties = &IPFilterProperties{
+			Enabled: true,
+		}
+	}
+
+	if managementProperties == nil {
+		managementProperties = &ManagementServerProperties{
+			Port: DefaultManagementServerPort,
+		}
+	}
+
+	return IPFilterController{
+		ruleSet,
+		properties,
+		managementProperties,
+	}
+}
+
+func (controller IPFilterController) RegisterManagementHandlers(registry HandlerRegistry) {
+	if !controller.properties.Enabled || controller.managementProperties.ServeOnMainPort || controller.managementProperties.Port == 0 {
+		return
+	}
+
+	registry.Register(Get(controller.getRules, Path("/ip-filter")))
+}
+
+func (controller IPFilterController) getRules(ctx *WebRequestContext) {
+	ctx.Ok().
+		SetModel(describeIPFilterRules(controller.ruleSet.GetRules())).
+		SetResponseContentType(MediaTypeApplicationJson)
+}
+
+func describeIPFilterRules(rules []IPFilterRule) []ipFilterRuleDefinition {
+	definitions := make([]ipFilterRuleDefinition, 0, len(rules))
+	for _, rule := range rules {
+		definitions = append(definitions, ipFilterRuleDefinition{
+			Pattern: rule.Pattern,
+			Allow:   describeNetworks(rule.Allow),
+			Deny:    describeNetworks(rule.Deny),
+		})
+	}
+	return definitions
+}
+
+func describeNetworks(networks []*net.IPNet) []string {
+	values := make([]string, 0, len(networks))
+	for _, network := range networks {
+		values = append(values, network.String())
+	}
+	return values
+}
@@ -0,0 +1,194 @@
+package web
+
+import (
+	json "github.com/json-iterator/go"
+	context "github.com/procyon-projects/procyon-context"
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+type testIPFilterController struct {
+}
+
+func (controller testIPFilterController) RegisterHandlers(registry HandlerRegistry) {
+	registry.Register(
+		Get(controller.ok, Path("/internal/reports")),
+		Get(controller.ok, Path("/public/info")),
+	)
+}
+
+func (controller testIPFilterController) ok(ctx *WebRequestContext) {
+	ctx.Ok().SetModel("ok")
+}
+
+func serveTestIPFilterRequest(router *ProcyonRouter, uri string, clientIP string) int {
+	headers := map[string]string{HeaderXForwardedFor: clientIP}
+	return serveTestAdapterRequest(router, http.MethodGet, uri, headers, "").StatusCode()
+}
+
+func TestIPFilterRule_IsAllowed(t *testing.T) {
+	rule := NewIPFilterRule("/internal/**").
+		WithAllow("10.0.0.0/8", "192.168.1.10").
+		WithDeny("10.0.5.0/24")
+
+	assert.True(t, rule.IsAllowed(net.ParseIP("10.1.2.3")))
+	assert.True(t, rule.IsAllowed(net.ParseIP("192.168.1.10")))
+	assert.False(t, rule.IsAllowed(net.ParseIP("192.168.1.11")))
+	assert.False(t, rule.IsAllowed(net.ParseIP("10.0.5.7")))
+	assert.False(t, rule.IsAllowed(nil))
+
+	denyOnly := NewIPFilterRule("/**").WithDeny("203.0.113.0/24")
+	assert.True(t, denyOnly.IsAllowed(net.ParseIP("198.51.100.1")))
+	assert.False(t, denyOnly.IsAllowed(net.ParseIP("203.0.113.9")))
+
+	assert.Panics(t, func() {
+		NewIPFilterRule("/internal/**").WithAllow("10.0.0.0/33")
+	})
+}
+
+type testIPFilterInterceptor struct {
+	handled *bool
+}
+
+func (interceptor testIPFilterInterceptor) GetPriority() core.PriorityValue {
+	return core.PriorityHighest
+}
+
+func (interceptor testIPFilterInterceptor) InterceptsUnmatchedRequests() bool {
+	return true
+}
+
+func (interceptor testIPFilterInterceptor) HandleBefore(ctx *WebRequestContext) {
+	*interceptor.handled = true
+}
+
+func TestRouter_IPFilter(t *testing.T) {
+	ruleSet := NewIPFilterRuleSet(nil)
+	ruleSet.SetRules(NewIPFilterRule("/internal/**").WithAllow("10.0.0.0/8").WithDeny("10.0.5.0/24"))
+
+	handled := false
+	router := NewRouter(
+		WithControllers(testIPFilterController{}),
+		WithInterceptors(testIPFilterInterceptor{&handled}),
+		WithIPFilter(ruleSet),
+		WithTrustedProxies("0.0.0.0/32"),
+	)
+
+	assert.Equal(t, http.StatusOK, serveTestIPFilterRequest(router, "/internal/reports", "10.1.2.3"))
+	assert.Equal(t, http.StatusForbidden, serveTestIPFilterRequest(router, "/internal/reports", "10.0.5.7"))
+	assert.Equal(t, http.StatusForbidden, serveTestIPFilterRequest(router, "/internal/reports", "198.51.100.1"))
+	assert.Equal(t, http.StatusOK, serveTestIPFilterRequest(router, "/public/info", "198.51.100.1"))
+	assert.Equal(t, http.StatusNotFound, serveTestIPFilterRequest(router, "/internal/unknown", "10.1.2.3"))
+
+	handled = false
+	assert.Equal(t, http.StatusForbidden, serveTestIPFilterRequest(router, "/internal/unknown", "198.51.100.1"))
+	assert.False(t, handled)
+
+	router = NewRouter(
+		WithControllers(testIPFilterController{}),
+		WithIPFilter(ruleSet),
+	)
+	assert.Equal(t, http.StatusForbidden, serveTestIPFilterRequest(router, "/internal/reports", "10.1.2.3"))
+
+	router = NewRouter(WithControllers(testIPFilterController{}))
+	assert.Equal(t, http.StatusOK, serveTestIPFilterRequest(router, "/internal/reports", "198.51.100.1"))
+}
+
+func TestIPFilterRuleSet_Reload(t *testing.T) {
+	file, err := ioutil.TempFile("", "ip-filter-*.json")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name())
+
+	modTime := time.Now().Add(-time.Hour)
+	writeRules := func(content string) {
+		assert.Nil(t, ioutil.WriteFile(file.Name(), []byte(content), 0600))
+		modTime = modTime.Add(time.Minute)
+		assert.Nil(t, os.Chtimes(file.Name(), modTime, modTime))
+	}
+
+	writeRules(`{"rules":[{"pattern":"/internal/**","allow":["10.0.0.0/8"]}]}`)
+	ruleSet := NewIPFilterRuleSet(&IPFilterProperties{Enabled: true, RulesFile: file.Name(), ReloadInterval: 10})
+	ruleSet.reloadInterval = time.Nanosecond
+	router := NewRouter(
+		WithControllers(testIPFilterController{}),
+		WithIPFilter(ruleSet),
+		WithTrustedProxies("0.0.0.0/32"),
+	)
+	managementRouter := NewRouter(WithManagementControllers(NewIPFilterController(ruleSet, nil, nil)))
+
+	assert.Equal(t, http.StatusForbidden, serveTestIPFilterRequest(router, "/internal/reports", "172.16.0.1"))
+
+	writeRules(`{"rules":[{"pattern":"/internal/**","allow":["10.0.0.0/8","172.16.0.0/12"]}]}`)
+	assert.Equal(t, http.StatusOK, serveTestIPFilterRequest(router, "/internal/reports", "172.16.0.1"))
+
+	response := serveTestAdapterRequest(managementRouter, http.MethodGet, "/ip-filter", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+
+	rules := make([]map[string]interface{}, 0)
+	assert.Nil(t, json.Unmarshal(response.Body(), &rules))
+	assert.Len(t, rules, 1)
+	assert.Equal(t, []interface{}{"10.0.0.0/8", "172.16.0.0/12"}, rules[0]["allow"])
+
+	response = serveTestAdapterRequest(managementRouter, http.MethodPost, "/ip-filter/reload", nil, "")
+	assert.NotEqual(t, http.StatusOK, response.StatusCode())
+
+	loggedErrors := make([]string, 0)
+	router.logger = testErrorLogger{context.NewSimpleLogger(), &loggedErrors}
+	writeRules(`{"rules":[{"pattern":"/internal/**","allow":["not-an-ip"]}]}`)
+	assert.Equal(t, http.StatusOK, serveTestIPFilterRequest(router, "/internal/reports", "172.16.0.1"))
+	assert.Equal(t, http.StatusOK, serveTestIPFilterRequest(router, "/internal/reports", "172.16.0.1"))
+	assert.Len(t, loggedErrors, 1)
+	assert.Contains(t, loggedErrors[0], "IP filter rules could not be reloaded, keeping the previous rules")
+	assert.NotNil(t, ruleSet.Reload())
+
+	ruleSet.reloadInterval = time.Hour
+	writeRules(`{"rules":[]}`)
+	assert.Equal(t, http.StatusOK, serveTestIPFilterRequest(router, "/internal/reports", "172.16.0.1"))
+	assert.Nil(t, ruleSet.Reload())
+	assert.Empty(t, ruleSet.GetRules())
+
+	_, err = ParseIPFilterRules([]byte(`{"rules":[{"allow":["10.0.0.0/8"]}]}`))
+	assert.Equal(t, ErrEmptyIPFilterPattern, err)
+
+	assert.Panics(t, func() {
+		NewIPFilterRuleSet(&IPFilterProperties{RulesFile: file.Name() + ".missing"})
+	})
+}
+
+type testErrorLogger struct {
+	context.Logger
+	errors *[]string
+}
+
+func (logger testErrorLogger) Error(ctx interface{}, message interface{}) {
+	*logger.errors = append(*logger.errors, message.(string))
+}
+
+func TestIPFilterController_RegisteredOnDedicatedManagementPort(t *testing.T) {
+	ruleSet := NewIPFilterRuleSet(nil)
+	ruleSet.SetRules(NewIPFilterRule("/internal/**").WithAllow("10.0.0.0/8"))
+
+	serveRules := func(controller IPFilterController) int {
+		router := NewRouter(WithManagementControllers(controller))
+		return serveTestAdapterRequest(router, http.MethodGet, "/ip-filter", nil, "").StatusCode()
+	}
+
+	assert.Equal(t, http.StatusOK, serveRules(NewIPFilterController(ruleSet,
+		&IPFilterProperties{Enabled: true}, &ManagementServerProperties{Port: 8081})))
+	assert.Equal(t, http.StatusNotFound, serveRules(NewIPFilterController(ruleSet,
+		&IPFilterProperties{Enabled: false}, &ManagementServerProperties{Port: 8081})))
+	assert.Equal(t, http.StatusNotFound, serveRules(NewIPFilterController(ruleSet,
+		&IPFilterProperties{Enabled: true}, &ManagementServerProperties{Port: 8081, ServeOnMainPort: true})))
+	assert.Equal(t, http.StatusNotFound, serveRules(NewIPFilterController(ruleSet,
+		&IPFilterProperties{Enabled: true}, &ManagementServerProperties{Port: 0})))
+
+	assert.Panics(t, func() {
+		NewIPFilterController(nil, nil, nil)
+	})
+}
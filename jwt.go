@@ -0,0 +1,563 @@
+package web
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	json "github.com/json-iterator/go"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	JWTAlgorithmHS256 = "HS256"
+	JWTAlgorithmRS256 = "RS256"
+	JWTAlgorithmES256 = "ES256"
+
+	DefaultJWTClockSkew = time.Minute
+)
+
+var (
+	ErrMalformedToken       = errors.New("malformed token")
+	ErrUnsupportedAlgorithm = errors.New("unsupported token algorithm")
+	ErrUnknownSigningKey    = errors.New("unknown token signing key")
+	ErrInvalidSignature     = errors.New("invalid token signature")
+	ErrTokenExpired         = errors.New("token is expired")
+	ErrTokenNotYetValid     = errors.New("token is not valid yet")
+	ErrInvalidIssuer        = errors.New("invalid token issuer")
+	ErrInvalidAudience      = errors.New("invalid token audience")
+)
+
+type JWTClaims map[string]interface{}
+
+func (claims JWTClaims) GetString(name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+func (claims JWTClaims) GetStrings(name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if text, ok := item.(string); ok {
+				values = append(values, text)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func (claims JWTClaims) GetTime(name string) (time.Time, bool) {
+	value, ok := claims[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(value), 0), true
+}
+
+func (claims JWTClaims) GetSubject() string {
+	return claims.GetString("sub")
+}
+
+func (claims JWTClaims) GetIssuer() string {
+	return claims.GetString("iss")
+}
+
+func (claims JWTClaims) GetAudience() []string {
+	return claims.GetStrings("aud")
+}
+
+type JWTPrincipal struct {
+	Name   string
+	Claims JWTClaims
+}
+
+func (principal JWTPrincipal) GetName() string {
+	return principal.Name
+}
+
+type JWTKey struct {
+	Id        string
+	Algorithm string
+	Key       interface{}
+}
+
+func NewHMACKey(id string, secret []byte) JWTKey {
+	return JWTKey{Id: id, Algorithm: JWTAlgorithmHS256, Key: secret}
+}
+
+func NewPublicKey(id string, publicKey crypto.PublicKey) (JWTKey, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return JWTKey{Id: id, Algorithm: JWTAlgorithmRS256, Key: key}, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return JWTKey{}, ErrUnsupportedAlgorithm
+		}
+		return JWTKey{Id: id, Algorithm: JWTAlgorithmES256, Key: key}, nil
+	default:
+		return JWTKey{}, ErrUnsupportedAlgorithm
+	}
+}
+
+func ParsePublicKeyPEM(id string, data []byte) (JWTKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return JWTKey{}, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		publicKey, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return JWTKey{}, err
+		}
+		return NewPublicKey(id, publicKey)
+	case "CERTIFICATE":
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return JWTKey{}, err
+		}
+		return NewPublicKey(id, certificate.PublicKey)
+	default:
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return JWTKey{}, err
+		}
+		return NewPublicKey(id, publicKey)
+	}
+}
+
+type JWTKeySet struct {
+	keys []JWTKey
+}
+
+func NewJWTKeySet(keys ...JWTKey) *JWTKeySet {
+	return &JWTKeySet{
+		keys: keys,
+	}
+}
+
+func (keySet *JWTKeySet) AddKey(keys ...JWTKey) {
+	keySet.keys = append(keySet.keys, keys...)
+}
+
+func (keySet *JWTKeySet) GetKeys() []JWTKey {
+	return keySet.keys
+}
+
+func (keySet *JWTKeySet) findKeys(id string, algorithm string) []JWTKey {
+	keys := make([]JWTKey, 0, 1)
+	for _, key := range keySet.keys {
+		if key.Algorithm != algorithm {
+			continue
+		}
+
+		if id == "" || key.Id == id {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	Id        string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	K         string `json:"k"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+}
+
+func ParseJWKS(data []byte) (*JWTKeySet, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	keySet := NewJWTKeySet()
+	for _, webKey := range document.Keys {
+		if webKey.Use != "" && webKey.Use != "sig" {
+			continue
+		}
+
+		key, err := webKey.toJWTKey()
+		if err != nil {
+			return nil, errors.New("invalid JSON web key " + webKey.Id + " : " + err.Error())
+		}
+
+		if webKey.Algorithm != "" && webKey.Algorithm != key.Algorithm {
+			return nil, errors.New("invalid JSON web key " + webKey.Id + " : " + ErrUnsupportedAlgorithm.Error())
+		}
+		keySet.AddKey(key)
+	}
+	return keySet, nil
+}
+
+func LoadJWKSFile(path string) (*JWTKeySet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+func (webKey jsonWebKey) toJWTKey() (JWTKey, error) {
+	switch webKey.KeyType {
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(webKey.K)
+		if err != nil {
+			return JWTKey{}, err
+		}
+		return NewHMACKey(webKey.Id, secret), nil
+	case "RSA":
+		modulus, err := decodeBigInt(webKey.N)
+		if err != nil {
+			return JWTKey{}, err
+		}
+
+		exponent, err := decodeBigInt(webKey.E)
+		if err != nil {
+			return JWTKey{}, err
+		}
+		return NewPublicKey(webKey.Id, &rsa.PublicKey{N: modulus, E: int(exponent.Int64())})
+	case "EC":
+		if webKey.Curve != "P-256" {
+			return JWTKey{}, ErrUnsupportedAlgorithm
+		}
+
+		x, err := decodeBigInt(webKey.X)
+		if err != nil {
+			return JWTKey{}, err
+		}
+
+		y, err := decodeBigInt(webKey.Y)
+		if err != nil {
+			return JWTKey{}, err
+		}
+
+		publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if !publicKey.Curve.IsOnCurve(x, y) {
+			return JWTKey{}, errors.New("point is not on curve")
+		}
+		return NewPublicKey(webKey.Id, publicKey)
+	default:
+		return JWTKey{}, ErrUnsupportedAlgorithm
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+type JWTVerifier struct {
+	keySet           *JWTKeySet
+	issuer           string
+	audiences        []string
+	clockSkew        time.Duration
+	nameClaim        string
+	authoritiesClaim string
+	clock            func() time.Time
+}
+
+func NewJWTVerifier(keySet *JWTKeySet) *JWTVerifier {
+	if keySet == nil {
+		panic("Key set must not be null")
+	}
+
+	return &JWTVerifier{
+		keySet:           keySet,
+		clockSkew:        DefaultJWTClockSkew,
+		nameClaim:        "sub",
+		authoritiesClaim: "scope",
+		clock:            time.Now,
+	}
+}
+
+func (verifier *JWTVerifier) WithIssuer(issuer string) *JWTVerifier {
+	verifier.issuer = issuer
+	return verifier
+}
+
+func (verifier *JWTVerifier) WithAudience(audiences ...string) *JWTVerifier {
+	verifier.audiences = append(verifier.audiences, audiences...)
+	return verifier
+}
+
+func (verifier *JWTVerifier) WithClockSkew(clockSkew time.Duration) *JWTVerifier {
+	verifier.clockSkew = clockSkew
+	return verifier
+}
+
+func (verifier *JWTVerifier) WithNameClaim(nameClaim string) *JWTVerifier {
+	verifier.nameClaim = nameClaim
+	return verifier
+}
+
+func (verifier *JWTVerifier) WithAuthoritiesClaim(authoritiesClaim string) *JWTVerifier {
+	verifier.authoritiesClaim = authoritiesClaim
+	return verifier
+}
+
+func (verifier *JWTVerifier) Verify(token string) (JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyId     string `json:"kid"`
+	}
+
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if header.Algorithm != JWTAlgorithmHS256 && header.Algorithm != JWTAlgorithmRS256 && header.Algorithm != JWTAlgorithmES256 {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	keys := verifier.keySet.findKeys(header.KeyId, header.Algorithm)
+	if len(keys) == 0 {
+		return nil, ErrUnknownSigningKey
+	}
+
+	signingInput := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, key := range keys {
+		if verifyJWTSignature(key, signingInput, signature) {
+			verified = true
+			break
+		}
+	}
+
+	if !verified {
+		return nil, ErrInvalidSignature
+	}
+
+	claims := make(JWTClaims)
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if err := verifier.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func decodeJWTSegment(segment string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+func verifyJWTSignature(key JWTKey, signingInput []byte, signature []byte) bool {
+	switch publicKey := key.Key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, publicKey)
+		mac.Write(signingInput)
+		return hmac.Equal(mac.Sum(nil), signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(signingInput)
+		return rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature) == nil
+	case *ecdsa.PublicKey:
+		if len(signature) != 64 {
+			return false
+		}
+
+		digest := sha256.Sum256(signingInput)
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(publicKey, digest[:], r, s)
+	default:
+		return false
+	}
+}
+
+func (verifier *JWTVerifier) validateClaims(claims JWTClaims) error {
+	now := verifier.clock()
+
+	for _, name := range []string{"exp", "nbf"} {
+		if value, ok := claims[name]; ok {
+			if _, ok = value.(float64); !ok {
+				return ErrMalformedToken
+			}
+		}
+	}
+
+	if expiresAt, ok := claims.GetTime("exp"); ok && !now.Before(expiresAt.Add(verifier.clockSkew)) {
+		return ErrTokenExpired
+	}
+
+	if notBefore, ok := claims.GetTime("nbf"); ok && now.Add(verifier.clockSkew).Before(notBefore) {
+		return ErrTokenNotYetValid
+	}
+
+	if verifier.issuer != "" && claims.GetIssuer() != verifier.issuer {
+		return ErrInvalidIssuer
+	}
+
+	if len(verifier.audiences) != 0 && !containsAny(claims.GetAudience(), verifier.audiences) {
+		return ErrInvalidAudience
+	}
+	return nil
+}
+
+func containsAny(values []string, candidates []string) bool {
+	for _, value := range values {
+		for _, candidate := range candidates {
+			if value == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (verifier *JWTVerifier) Authenticate(token string) (*Authentication, error) {
+	claims, err := verifier.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	name := claims.GetString(verifier.nameClaim)
+	if name == "" {
+		return nil, ErrInvalidToken
+	}
+
+	authentication := NewAuthentication(JWTPrincipal{name, claims}, claims.GetStrings(verifier.authoritiesClaim)...)
+	authentication.Scheme = AuthenticationSchemeBearer
+	return authentication, nil
+}
+
+type JWTAuthenticator struct {
+	verifier      *JWTVerifier
+	authenticator BearerAuthenticator
+}
+
+func NewJWTAuthenticator(verifier *JWTVerifier) JWTAuthenticator {
+	if verifier == nil {
+		panic("Verifier must not be null")
+	}
+
+	return JWTAuthenticator{
+		verifier:      verifier,
+		authenticator: NewBearerAuthenticator(verifier.Authenticate),
+	}
+}
+
+func newJWTAuthenticator(properties *JWTProperties) JWTAuthenticator {
+	if properties == nil || !properties.Enabled {
+		return JWTAuthenticator{}
+	}
+
+	keySet := NewJWTKeySet()
+	if properties.Secret != "" {
+		keySet.AddKey(NewHMACKey("", []byte(properties.Secret)))
+	}
+
+	if properties.PublicKeyFile != "" {
+		data, err := ioutil.ReadFile(properties.PublicKeyFile)
+		if err != nil {
+			panic("Could not read public key file : " + err.Error())
+		}
+
+		key, err := ParsePublicKeyPEM("", data)
+		if err != nil {
+			panic("Invalid public key file : " + err.Error())
+		}
+		keySet.AddKey(key)
+	}
+
+	if properties.JWKSFile != "" {
+		jwks, err := LoadJWKSFile(properties.JWKSFile)
+		if err != nil {
+			panic("Invalid JWKS file : " + err.Error())
+		}
+		keySet.AddKey(jwks.GetKeys()...)
+	}
+
+	if len(keySet.GetKeys()) == 0 {
+		panic("JWT authentication requires a secret, a public key file or a JWKS file")
+	}
+
+	verifier := NewJWTVerifier(keySet).
+		WithIssuer(properties.Issuer).
+		WithClockSkew(time.Duration(properties.ClockSkew) * time.Second)
+
+	for _, audience := range strings.Split(properties.Audience, ",") {
+		if audience = strings.TrimSpace(audience); audience != "" {
+			verifier.WithAudience(audience)
+		}
+	}
+
+	if properties.NameClaim != "" {
+		verifier.WithNameClaim(properties.NameClaim)
+	}
+
+	if properties.AuthoritiesClaim != "" {
+		verifier.WithAuthoritiesClaim(properties.AuthoritiesClaim)
+	}
+	return NewJWTAuthenticator(verifier)
+}
+
+func (authenticator JWTAuthenticator) WithRealm(realm string) JWTAuthenticator {
+	authenticator.authenticator = authenticator.authenticator.WithRealm(realm)
+	return authenticator
+}
+
+func (authenticator JWTAuthenticator) GetScheme() string {
+	return AuthenticationSchemeBearer
+}
+
+func (authenticator JWTAuthenticator) GetChallenge(err error) string {
+	if authenticator.verifier == nil {
+		return ""
+	}
+	return authenticator.authenticator.GetChallenge(err)
+}
+
+func (authenticator JWTAuthenticator) Authenticate(ctx *WebRequestContext) (*Authentication, error) {
+	if authenticator.verifier == nil {
+		return nil, nil
+	}
+	return authenticator.authenticator.Authenticate(ctx)
+}
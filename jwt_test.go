@@ -0,0 +1,248 @@
+package web
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	json "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+var testJWTNow = time.Unix(1700000000, 0)
+
+func signTestJWT(t *testing.T, algorithm string, keyId string, key interface{}, claims JWTClaims) string {
+	header := map[string]string{"alg": algorithm, "typ": "JWT"}
+	if keyId != "" {
+		header["kid"] = keyId
+	}
+
+	encodedHeader, _ := json.Marshal(header)
+	encodedClaims, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(encodedHeader) + "." + base64.RawURLEncoding.EncodeToString(encodedClaims)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch privateKey := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, privateKey)
+		mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+		assert.Nil(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest[:])
+		assert.Nil(t, err)
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func testJWTClaims() JWTClaims {
+	return JWTClaims{
+		"sub":   "procyon",
+		"iss":   "https://issuer.procyon.io",
+		"aud":   []string{"orders", "payments"},
+		"exp":   testJWTNow.Add(time.Hour).Unix(),
+		"nbf":   testJWTNow.Add(-time.Minute).Unix(),
+		"scope": "orders:read orders:write",
+	}
+}
+
+func newTestJWTVerifier(keys ...JWTKey) *JWTVerifier {
+	verifier := NewJWTVerifier(NewJWTKeySet(keys...)).
+		WithIssuer("https://issuer.procyon.io").
+		WithAudience("orders").
+		WithClockSkew(30 * time.Second)
+	verifier.clock = func() time.Time {
+		return testJWTNow
+	}
+	return verifier
+}
+
+func TestJWTVerifier_Algorithms(t *testing.T) {
+	secret := []byte("a-very-secret-key-for-hs256-tests")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	rsaPublicKey, err := NewPublicKey("rsa-1", &rsaKey.PublicKey)
+	assert.Nil(t, err)
+	ecdsaPublicKey, err := NewPublicKey("ec-1", &ecdsaKey.PublicKey)
+	assert.Nil(t, err)
+	verifier := newTestJWTVerifier(NewHMACKey("hmac-1", secret), rsaPublicKey, ecdsaPublicKey)
+
+	claims, err := verifier.Verify(signTestJWT(t, JWTAlgorithmHS256, "hmac-1", secret, testJWTClaims()))
+	assert.Nil(t, err)
+	assert.Equal(t, "procyon", claims.GetSubject())
+
+	_, err = verifier.Verify(signTestJWT(t, JWTAlgorithmRS256, "rsa-1", rsaKey, testJWTClaims()))
+	assert.Nil(t, err)
+
+	_, err = verifier.Verify(signTestJWT(t, JWTAlgorithmES256, "", ecdsaKey, testJWTClaims()))
+	assert.Nil(t, err)
+
+	_, err = verifier.Verify(signTestJWT(t, JWTAlgorithmHS256, "hmac-1", []byte("another-secret"), testJWTClaims()))
+	assert.Equal(t, ErrInvalidSignature, err)
+
+	_, err = verifier.Verify(signTestJWT(t, JWTAlgorithmRS256, "rsa-2", rsaKey, testJWTClaims()))
+	assert.Equal(t, ErrUnknownSigningKey, err)
+
+	_, err = verifier.Verify(signTestJWT(t, "none", "", nil, testJWTClaims()))
+	assert.Equal(t, ErrUnsupportedAlgorithm, err)
+
+	_, err = verifier.Verify("not-a-token")
+	assert.Equal(t, ErrMalformedToken, err)
+}
+
+func TestJWTVerifier_Claims(t *testing.T) {
+	secret := []byte("a-very-secret-key-for-hs256-tests")
+	verifier := newTestJWTVerifier(NewHMACKey("", secret))
+
+	claims := testJWTClaims()
+	claims["exp"] = testJWTNow.Add(-10 * time.Second).Unix()
+	_, err := verifier.Verify(signTestJWT(t, JWTAlgorithmHS256, "", secret, claims))
+	assert.Nil(t, err)
+
+	claims["exp"] = testJWTNow.Add(-time.Minute).Unix()
+	_, err = verifier.Verify(signTestJWT(t, JWTAlgorithmHS256, "", secret, claims))
+	assert.Equal(t, ErrTokenExpired, err)
+
+	claims = testJWTClaims()
+	claims["nbf"] = testJWTNow.Add(time.Minute).Unix()
+	_, err = verifier.Verify(signTestJWT(t, JWTAlgorithmHS256, "", secret, claims))
+	assert.Equal(t, ErrTokenNotYetValid, err)
+
+	claims = testJWTClaims()
+	claims["exp"] = "tomorrow"
+	_, err = verifier.Verify(signTestJWT(t, JWTAlgorithmHS256, "", secret, claims))
+	assert.Equal(t, ErrMalformedToken, err)
+
+	claims = testJWTClaims()
+	claims["nbf"] = nil
+	_, err = verifier.Verify(signTestJWT(t, JWTAlgorithmHS256, "", secret, claims))
+	assert.Equal(t, ErrMalformedToken, err)
+
+	claims = testJWTClaims()
+	claims["iss"] = "https://other.procyon.io"
+	_, err = verifier.Verify(signTestJWT(t, JWTAlgorithmHS256, "", secret, claims))
+	assert.Equal(t, ErrInvalidIssuer, err)
+
+	claims = testJWTClaims()
+	claims["aud"] = "billing"
+	_, err = verifier.Verify(signTestJWT(t, JWTAlgorithmHS256, "", secret, claims))
+	assert.Equal(t, ErrInvalidAudience, err)
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	encode := func(value *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(value.Bytes())
+	}
+
+	jwks, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "alg": "RS256", "use": "sig", "n": encode(rsaKey.N), "e": encode(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": encode(ecdsaKey.X), "y": encode(ecdsaKey.Y)},
+			{"kty": "oct", "kid": "hmac-1", "k": base64.RawURLEncoding.EncodeToString([]byte("jwks-secret"))},
+			{"kty": "RSA", "kid": "rsa-enc", "use": "enc", "n": encode(rsaKey.N), "e": "AQAB"},
+		},
+	})
+
+	file, err := ioutil.TempFile("", "jwks-*.json")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name())
+	_, _ = file.Write(jwks)
+	_ = file.Close()
+
+	keySet, err := LoadJWKSFile(file.Name())
+	assert.Nil(t, err)
+	assert.Len(t, keySet.GetKeys(), 3)
+
+	verifier := newTestJWTVerifier(keySet.GetKeys()...)
+	_, err = verifier.Verify(signTestJWT(t, JWTAlgorithmRS256, "rsa-1", rsaKey, testJWTClaims()))
+	assert.Nil(t, err)
+	_, err = verifier.Verify(signTestJWT(t, JWTAlgorithmES256, "ec-1", ecdsaKey, testJWTClaims()))
+	assert.Nil(t, err)
+	_, err = verifier.Verify(signTestJWT(t, JWTAlgorithmHS256, "hmac-1", []byte("jwks-secret"), testJWTClaims()))
+	assert.Nil(t, err)
+
+	_, err = ParseJWKS([]byte(`{"keys":[{"kty":"EC","kid":"ec-2","crv":"P-384","x":"AA","y":"AA"}]}`))
+	assert.NotNil(t, err)
+}
+
+func TestParsePublicKeyPEM(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	encodedKey, err := x509.MarshalPKIXPublicKey(&ecdsaKey.PublicKey)
+	assert.Nil(t, err)
+
+	key, err := ParsePublicKeyPEM("ec-1", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: encodedKey}))
+	assert.Nil(t, err)
+	assert.Equal(t, JWTAlgorithmES256, key.Algorithm)
+
+	_, err = ParsePublicKeyPEM("ec-1", []byte("not a pem"))
+	assert.NotNil(t, err)
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	secret := []byte("a-very-secret-key-for-hs256-tests")
+	authenticator := NewJWTAuthenticator(newTestJWTVerifier(NewHMACKey("", secret)))
+	router := NewRouter(WithControllers(testAuthenticationController{}), WithAuthenticators(authenticator))
+
+	token := signTestJWT(t, JWTAlgorithmHS256, "", secret, testJWTClaims())
+	ctx := NewWebRequestContext(RequestMethodGet, "/me", ContextHeader("Authorization", "Bearer "+token))
+	authentication, err := authenticator.Authenticate(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"orders:read", "orders:write"}, authentication.Authorities)
+	assert.Equal(t, "https://issuer.procyon.io", authentication.Principal.(JWTPrincipal).Claims.GetIssuer())
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/me", map[string]string{"Authorization": "Bearer " + token}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.Equal(t, "Bearer:procyon", string(response.Body()))
+
+	claims := testJWTClaims()
+	claims["exp"] = testJWTNow.Add(-time.Hour).Unix()
+	token = signTestJWT(t, JWTAlgorithmHS256, "", secret, claims)
+	response = serveTestAdapterRequest(router, http.MethodGet, "/me", map[string]string{"Authorization": "Bearer " + token}, "")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode())
+	assert.Equal(t, `Bearer realm="procyon", error="invalid_token"`, string(response.Header.Peek("WWW-Authenticate")))
+}
+
+func TestNewJWTAuthenticatorFromProperties(t *testing.T) {
+	authenticator := newJWTAuthenticator(&JWTProperties{})
+	ctx := NewWebRequestContext(RequestMethodGet, "/me", ContextHeader("Authorization", "Bearer token"))
+	authentication, err := authenticator.Authenticate(ctx)
+	assert.Nil(t, authentication)
+	assert.Nil(t, err)
+	assert.Empty(t, authenticator.GetChallenge(nil))
+
+	assert.Panics(t, func() {
+		newJWTAuthenticator(&JWTProperties{Enabled: true})
+	})
+
+	authenticator = newJWTAuthenticator(&JWTProperties{Enabled: true, Secret: "secret", Audience: "orders, payments"})
+	assert.Equal(t, []string{"orders", "payments"}, authenticator.verifier.audiences)
+}
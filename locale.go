@@ -0,0 +1,152 @@
+package web
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type LocaleResolver interface {
+	ResolveLocale(ctx *WebRequestContext) string
+}
+
+type DefaultLocaleResolver struct {
+	defaultLocale    string
+	supportedLocales []string
+	parameterName    string
+	cookieName       string
+}
+
+func NewDefaultLocaleResolver(defaultLocale string, supportedLocales ...string) DefaultLocaleResolver {
+	if defaultLocale == "" {
+		defaultLocale = DefaultLocale
+	}
+
+	resolver := DefaultLocaleResolver{
+		defaultLocale:    defaultLocale,
+		supportedLocales: make([]string, 0),
+	}
+
+	for _, locale := range supportedLocales {
+		locale = strings.TrimSpace(locale)
+		if locale != "" {
+			resolver.supportedLocales = append(resolver.supportedLocales, locale)
+		}
+	}
+
+	if len(resolver.supportedLocales) == 0 {
+		resolver.supportedLocales = append(resolver.supportedLocales, defaultLocale)
+	}
+
+	return resolver
+}
+
+func (resolver DefaultLocaleResolver) WithParameterName(parameterName string) DefaultLocaleResolver {
+	resolver.parameterName = parameterName
+	return resolver
+}
+
+func (resolver DefaultLocaleResolver) WithCookieName(cookieName string) DefaultLocaleResolver {
+	resolver.cookieName = cookieName
+	return resolver
+}
+
+func (resolver DefaultLocaleResolver) ResolveLocale(ctx *WebRequestContext) string {
+	if resolver.parameterName != "" {
+		if value, ok := ctx.GetRequestParameter(resolver.parameterName); ok {
+			if locale, ok := resolver.match(value); ok {
+				return locale
+			}
+		}
+	}
+
+	if resolver.cookieName != "" {
+		if value := ctx.fastHttpRequestContext.Request.Header.Cookie(resolver.cookieName); len(value) != 0 {
+			if locale, ok := resolver.match(string(value)); ok {
+				return locale
+			}
+		}
+	}
+
+	if acceptLanguage, ok := ctx.GetRequestHeader("Accept-Language"); ok {
+		for _, languageRange := range parseAcceptLanguage(acceptLanguage) {
+			if languageRange == "*" {
+				return resolver.defaultLocale
+			}
+
+			if locale, ok := resolver.match(languageRange); ok {
+				return locale
+			}
+		}
+	}
+
+	return resolver.defaultLocale
+}
+
+func (resolver DefaultLocaleResolver) match(locale string) (string, bool) {
+	locale = strings.Replace(strings.TrimSpace(locale), "_", "-", -1)
+	if locale == "" {
+		return "", false
+	}
+
+	for _, supportedLocale := range resolver.supportedLocales {
+		if strings.EqualFold(supportedLocale, locale) {
+			return supportedLocale, true
+		}
+	}
+
+	language := locale
+	if index := strings.Index(locale, "-"); index != -1 {
+		language = locale[:index]
+	}
+
+	for _, supportedLocale := range resolver.supportedLocales {
+		if strings.EqualFold(supportedLocale, language) {
+			return supportedLocale, true
+		}
+	}
+
+	return "", false
+}
+
+type languageRange struct {
+	tag     string
+	quality float64
+}
+
+func parseAcceptLanguage(value string) []string {
+	ranges := make([]languageRange, 0)
+	for _, part := range strings.Split(value, ",") {
+		segments := strings.Split(part, ";")
+		tag := strings.TrimSpace(segments[0])
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, segment := range segments[1:] {
+			segment = strings.TrimSpace(segment)
+			if strings.HasPrefix(segment, "q=") {
+				parsedQuality, err := strconv.ParseFloat(segment[2:], 64)
+				if err != nil {
+					parsedQuality = 0
+				}
+				quality = parsedQuality
+			}
+		}
+
+		if quality > 0 {
+			ranges = append(ranges, languageRange{tag, quality})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	tags := make([]string, 0, len(ranges))
+	for _, languageRange := range ranges {
+		tags = append(tags, languageRange.tag)
+	}
+	return tags
+}
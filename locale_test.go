@@ -0,0 +1,109 @@
+package web
+
+import (
+	"github.com/procyon-projects/procyon-context"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"net/http"
+	"testing"
+)
+
+func newTestLocaleRequestContext(uri string, acceptLanguage string) *WebRequestContext {
+	ctx := &WebRequestContext{
+		router: &ProcyonRouter{
+			responseBodyWriter: newDefaultResponseBodyWriter(),
+			localeResolver:     NewDefaultLocaleResolver("en", "en", "tr", "de").WithParameterName("lang").WithCookieName("lang"),
+			messageSource:      NewTranslatorMessageSource(),
+		},
+	}
+	ctx.fastHttpRequestContext = &fasthttp.RequestCtx{}
+	ctx.fastHttpRequestContext.Request.SetRequestURI(uri)
+	if acceptLanguage != "" {
+		ctx.fastHttpRequestContext.Request.Header.Set("Accept-Language", acceptLanguage)
+	}
+	ctx.prepare(true)
+	return ctx
+}
+
+func TestDefaultLocaleResolver_ResolveLocale(t *testing.T) {
+	assert.Equal(t, "de", newTestLocaleRequestContext("/orders", "fr-FR, de-DE;q=0.8, tr;q=0.5").GetLocale())
+	assert.Equal(t, "tr", newTestLocaleRequestContext("/orders", "de;q=0.2, tr-TR").GetLocale())
+	assert.Equal(t, "en", newTestLocaleRequestContext("/orders", "fr, *;q=0.1").GetLocale())
+	assert.Equal(t, "en", newTestLocaleRequestContext("/orders", "").GetLocale())
+	assert.Equal(t, "tr", newTestLocaleRequestContext("/orders?lang=tr", "de").GetLocale())
+
+	ctx := newTestLocaleRequestContext("/orders", "de")
+	ctx.fastHttpRequestContext.Request.Header.SetCookie("lang", "tr-TR")
+	assert.Equal(t, "tr", ctx.GetLocale())
+}
+
+func TestTranslatorMessageSource_GetMessage(t *testing.T) {
+	messageSource := NewTranslatorMessageSource()
+	assert.Nil(t, messageSource.AddMessages("tr", map[string]string{"order.not-found": "{0} numaralı sipariş bulunamadı"}))
+
+	message, ok := messageSource.GetMessage("tr-TR", "order.not-found", "42")
+	assert.True(t, ok)
+	assert.Equal(t, "42 numaralı sipariş bulunamadı", message)
+
+	message, ok = messageSource.GetMessage("fr", "http.status.404")
+	assert.True(t, ok)
+	assert.Equal(t, "Not Found", message)
+
+	_, ok = messageSource.GetMessage("de", "order.not-found", "42")
+	assert.False(t, ok)
+}
+
+func TestDefaultErrorHandler_HandleLocalizedErrors(t *testing.T) {
+	handler := NewDefaultErrorHandler(context.NewSimpleLogger())
+
+	ctx := newTestLocaleRequestContext("/orders/1", "de")
+	handler.HandleError(HttpErrorNotFound, ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, "Not Found", HttpErrorNotFound.Message)
+	assert.Equal(t, "{\"Code\":404,\"Message\":\"Nicht gefunden\"}", string(ctx.GetResponseBody()))
+
+	ctx = newTestLocaleRequestContext("/orders", "tr")
+	handler.HandleError(newDefaultValidator(ctx.router.messageSource).Validate(testProblemRequest{Count: 3}), ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, http.StatusBadRequest, ctx.fastHttpRequestContext.Response.StatusCode())
+	assert.Equal(t, "{\"Code\":400,\"Message\":\"Doğrulama başarısız oldu\",\"Errors\":[{\"field\":\"name\","+
+		"\"rule\":\"required\",\"message\":\"name zorunlu bir alandır\"}]}", string(ctx.GetResponseBody()))
+}
+
+func TestProblemDetailsErrorHandler_HandleLocalizedError(t *testing.T) {
+	handler := NewProblemDetailsErrorHandler(context.NewSimpleLogger())
+	ctx := newTestLocaleRequestContext("/orders?lang=de", "tr")
+
+	handler.HandleError(HttpErrorTooManyRequests, ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, "{\"type\":\"about:blank\",\"title\":\"Zu viele Anfragen\",\"status\":429,"+
+		"\"instance\":\"/orders\"}", string(ctx.GetResponseBody()))
+}
+
+func TestTranslatorMessageSource_TranslateValidationErrors(t *testing.T) {
+	handler := NewDefaultErrorHandler(context.NewSimpleLogger())
+	ctx := newTestLocaleRequestContext("/orders", "tr")
+
+	messageSource := ctx.router.messageSource.(*TranslatorMessageSource)
+	assert.Nil(t, messageSource.AddMessages("tr", map[string]string{"validation.min": "{0} en az {1} olmalı"}))
+
+	handler.HandleError(newDefaultValidator(messageSource).Validate(testProblemRequest{Count: 1}), ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, "{\"Code\":400,\"Message\":\"Doğrulama başarısız oldu\",\"Errors\":[{\"field\":\"name\",\"rule\":\"required\","+
+		"\"message\":\"name zorunlu bir alandır\"},{\"field\":\"count\",\"rule\":\"min\",\"param\":\"3\",\"message\":\"count en az 3 olmalı\"}]}",
+		string(ctx.GetResponseBody()))
+
+	ctx = newTestLocaleRequestContext("/orders", "fr")
+	ctx.router.messageSource = messageSource
+	handler.HandleError(newDefaultValidator(messageSource).Validate(testProblemRequest{Name: "order", Count: 1}), ctx)
+	ctx.writeResponse()
+
+	assert.Equal(t, "{\"Code\":400,\"Message\":\"Validation failed\",\"Errors\":[{\"field\":\"count\",\"rule\":\"min\",\"param\":\"3\","+
+		"\"message\":\"count must be 3 or greater\"}]}", string(ctx.GetResponseBody()))
+
+	assert.True(t, NewWebRequestContext(RequestMethodGet, "/orders").router.messageSource == NewWebRequestContext(RequestMethodGet, "/orders").router.messageSource)
+}
@@ -0,0 +1,177 @@
+package web
+
+import (
+	"sync"
+)
+
+const DefaultManagementServerPort uint = 8081
+
+type ManagementController interface {
+	RegisterManagementHandlers(registry HandlerRegistry)
+}
+
+type ManagementInterceptor interface {
+	IsManagementInterceptor() bool
+}
+
+type managementControllerAdapter struct {
+	controller ManagementController
+}
+
+func (adapter managementControllerAdapter) RegisterHandlers(registry HandlerRegistry) {
+	adapter.controller.RegisterManagementHandlers(registry)
+}
+
+func isManagementInterceptor(pea interface{}) bool {
+	interceptor, ok := pea.(ManagementInterceptor)
+	return ok && interceptor.IsManagementInterceptor()
+}
+
+type ManagementRegistry interface {
+	RegisterManagementController(controllers ...ManagementController)
+	RegisterManagementInterceptor(interceptors ...interface{})
+	GetManagementControllers() []ManagementController
+	GetManagementInterceptors() []interface{}
+}
+
+type SimpleManagementRegistry struct {
+	controllers  []ManagementController
+	interceptors []interface{}
+	mu           sync.RWMutex
+}
+
+func NewSimpleManagementRegistry() *SimpleManagementRegistry {
+	return &SimpleManagementRegistry{
+		controllers:  make([]ManagementController, 0),
+		interceptors: make([]interface{}, 0),
+	}
+}
+
+func (registry *SimpleManagementRegistry) RegisterManagementController(controllers ...ManagementController) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for _, controller := range controllers {
+		if controller == nil {
+			panic("Management controller must not be null")
+		}
+		registry.controllers = append(registry.controllers, controller)
+	}
+}
+
+func (registry *SimpleManagementRegistry) RegisterManagementInterceptor(interceptors ...interface{}) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for _, interceptor := range interceptors {
+		if interceptor == nil {
+			panic("Management interceptor must not be null")
+		}
+		registry.interceptors = append(registry.interceptors, interceptor)
+	}
+}
+
+func (registry *SimpleManagementRegistry) GetManagementControllers() []ManagementController {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	controllers := make([]ManagementController, len(registry.controllers))
+	copy(controllers, registry.controllers)
+	return controllers
+}
+
+func (registry *SimpleManagementRegistry) GetManagementInterceptors() []interface{} {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	interceptors := make([]interface{}, len(registry.interceptors))
+	copy(interceptors, registry.interceptors)
+	return interceptors
+}
+
+func newManagementInterceptorRegistry(interceptorRegistry HandlerInterceptorRegistry, interceptors []interface{}) HandlerInterceptorRegistry {
+	if len(interceptors) == 0 {
+		return interceptorRegistry
+	}
+
+	if simpleRegistry, ok := interceptorRegistry.(*SimpleHandlerInterceptorRegistry); ok {
+		managementRegistry := simpleRegistry.clone()
+		for _, interceptor := range interceptors {
+			managementRegistry.RegisterHandlerInterceptor(interceptor)
+		}
+		return managementRegistry
+	}
+
+	managementRegistry := NewSimpleHandlerInterceptorRegistry()
+	for _, interceptor := range interceptors {
+		managementRegistry.RegisterHandlerInterceptor(interceptor)
+	}
+
+	if interceptorRegistry == nil {
+		return managementRegistry
+	}
+
+	return composedInterceptorRegistry{
+		first:  managementRegistry,
+		second: interceptorRegistry,
+	}
+}
+
+type composedInterceptorRegistry struct {
+	first  HandlerInterceptorRegistry
+	second HandlerInterceptorRegistry
+}
+
+func (registry composedInterceptorRegistry) RegisterHandlerInterceptor(interceptor interface{}) {
+	registry.second.RegisterHandlerInterceptor(interceptor)
+}
+
+func (registry composedInterceptorRegistry) GetHandlerBeforeInterceptors() []HandlerInterceptor {
+	return append(registry.first.GetHandlerBeforeInterceptors(), registry.second.GetHandlerBeforeInterceptors()...)
+}
+
+func (registry composedInterceptorRegistry) GetHandlerAfterInterceptors() []HandlerInterceptor {
+	return append(registry.second.GetHandlerAfterInterceptors(), registry.first.GetHandlerAfterInterceptors()...)
+}
+
+func (registry composedInterceptorRegistry) GetHandlerAfterCompletionInterceptors() []HandlerInterceptor {
+	return append(registry.second.GetHandlerAfterCompletionInterceptors(), registry.first.GetHandlerAfterCompletionInterceptors()...)
+}
+
+func registerManagementControllers(handlerMapping RequestHandlerMapping, registry ManagementRegistry) RequestHandlerMapping {
+	interceptorRegistry := newManagementInterceptorRegistry(handlerMapping.interceptorRegistry, registry.GetManagementInterceptors())
+	managementMapping := NewRequestHandlerMapping(handlerMapping.mappingRegistry, interceptorRegistry)
+	mappingProcessor := NewRequestHandlerMappingProcessor(managementMapping)
+	for _, controller := range registry.GetManagementControllers() {
+		_, _ = mappingProcessor.BeforePeaInitialization("", managementControllerAdapter{controller})
+	}
+	return managementMapping
+}
+
+func newManagementRouterFrom(router *ProcyonRouter, registry ManagementRegistry) *ProcyonRouter {
+	managementRouter := &ProcyonRouter{}
+	*managementRouter = *router
+	managementRouter.requestContextPool = &sync.Pool{
+		New: managementRouter.newWebRequestContext,
+	}
+
+	handlerMapping := NewRequestHandlerMapping(NewRequestMappingRegistry(), NewSimpleHandlerInterceptorRegistry())
+	managementRouter.handlerMapping = registerManagementControllers(handlerMapping, registry)
+	managementRouter.configureNotFoundChain()
+	return managementRouter
+}
+
+func resolveManagementPort(properties *ManagementServerProperties, serverPort uint) (uint, bool) {
+	if properties == nil {
+		return DefaultManagementServerPort, false
+	}
+
+	if properties.ServeOnMainPort {
+		return serverPort, true
+	}
+
+	if properties.Port != 0 && properties.Port == serverPort {
+		panic("Management port must differ from the server port, set server.management.serve-on-main-port to serve operational endpoints on the main port")
+	}
+	return properties.Port, false
+}
@@ -0,0 +1,110 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type testManagementInterceptor struct {
+}
+
+func (interceptor testManagementInterceptor) IsManagementInterceptor() bool {
+	return true
+}
+
+func (interceptor testManagementInterceptor) HandleBefore(ctx *WebRequestContext) {
+	if token, _ := ctx.GetRequestHeader("X-Management-Token"); token != "secret" {
+		ctx.SetHTTPError(HttpErrorUnauthorized)
+		ctx.Cancel()
+	}
+}
+
+func TestManagementProcessor(t *testing.T) {
+	registry := NewSimpleManagementRegistry()
+	processor := NewManagementProcessor(registry)
+
+	controller := NewHealthController(NewSimpleHealthIndicatorRegistry(), nil)
+	_, _ = processor.BeforePeaInitialization("healthController", controller)
+	_, _ = processor.BeforePeaInitialization("managementInterceptor", testManagementInterceptor{})
+	_, _ = processor.BeforePeaInitialization("interceptor", testWebSocketAuthInterceptor{})
+
+	assert.Equal(t, []ManagementController{controller}, registry.GetManagementControllers())
+	assert.Equal(t, []interface{}{testManagementInterceptor{}}, registry.GetManagementInterceptors())
+}
+
+func TestHandlerInterceptorProcessor_SkipsManagementInterceptors(t *testing.T) {
+	handlerMapping := NewRequestHandlerMapping(NewRequestMappingRegistry(), NewSimpleHandlerInterceptorRegistry())
+	_, _ = NewHandlerInterceptorProcessor(handlerMapping.interceptorRegistry).BeforePeaInitialization("managementInterceptor", testManagementInterceptor{})
+	_, _ = NewRequestHandlerMappingProcessor(handlerMapping).BeforePeaInitialization("controller", testNetHttpController{})
+
+	router := NewRouter()
+	router.handlerMapping = handlerMapping
+
+	response := serveTestAdapterRequest(router, http.MethodPost, "/echo", nil, "payload")
+	assert.Equal(t, http.StatusCreated, response.StatusCode())
+}
+
+func TestNewManagementRouterFrom(t *testing.T) {
+	registry := NewSimpleManagementRegistry()
+	registry.RegisterManagementController(NewHealthController(NewSimpleHealthIndicatorRegistry(), nil))
+	registry.RegisterManagementInterceptor(testManagementInterceptor{})
+
+	mainRouter := NewRouter(WithControllers(testNetHttpController{}), WithErrorProperties(&ErrorProperties{ProblemDetails: true}))
+	router := newManagementRouterFrom(mainRouter, registry)
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/health", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode())
+	assert.Equal(t, MediaTypeApplicationProblemJsonValue, string(response.Header.ContentType()))
+
+	response = serveTestAdapterRequest(router, http.MethodPost, "/echo", nil, "payload")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/health/liveness", map[string]string{"X-Management-Token": "secret"}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.JSONEq(t, "{\"status\":\"UP\"}", string(response.Body()))
+
+	publicRouter := NewRouter(WithControllers(testNetHttpController{}))
+	response = serveTestAdapterRequest(publicRouter, http.MethodGet, "/health", nil, "")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode())
+}
+
+func TestRegisterManagementControllers_OnMainPort(t *testing.T) {
+	registry := NewSimpleManagementRegistry()
+	registry.RegisterManagementController(NewHealthController(NewSimpleHealthIndicatorRegistry(), nil))
+	registry.RegisterManagementInterceptor(testManagementInterceptor{})
+
+	router := NewRouter(WithControllers(testNetHttpController{}))
+	registerManagementControllers(router.handlerMapping.(RequestHandlerMapping), registry)
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/health", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/health", map[string]string{"X-Management-Token": "secret"}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodPost, "/echo", nil, "payload")
+	assert.Equal(t, http.StatusCreated, response.StatusCode())
+}
+
+func TestResolveManagementPort(t *testing.T) {
+	port, onMainPort := resolveManagementPort(nil, 8080)
+	assert.Equal(t, DefaultManagementServerPort, port)
+	assert.False(t, onMainPort)
+
+	port, onMainPort = resolveManagementPort(&ManagementServerProperties{Port: 9090}, 8080)
+	assert.Equal(t, uint(9090), port)
+	assert.False(t, onMainPort)
+
+	port, onMainPort = resolveManagementPort(&ManagementServerProperties{Port: 0}, 8080)
+	assert.Equal(t, uint(0), port)
+	assert.False(t, onMainPort)
+
+	port, onMainPort = resolveManagementPort(&ManagementServerProperties{Port: 9090, ServeOnMainPort: true}, 8080)
+	assert.Equal(t, uint(8080), port)
+	assert.True(t, onMainPort)
+
+	assert.Panics(t, func() {
+		resolveManagementPort(&ManagementServerProperties{Port: 8080}, 8080)
+	})
+}
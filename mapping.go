@@ -41,8 +41,21 @@ func NewRequestHandlerMapping(mappingRegistry MappingRegistry, interceptorRegist
 }
 
 func (requestMapping RequestHandlerMapping) RegisterHandlerMethod(path string, method RequestMethod, handlerFunc RequestHandlerFunction, metadata *RequestObjectMetadata) {
-	handlerChain := NewHandlerChain(handlerFunc, requestMapping.interceptorRegistry, metadata)
-	requestMapping.mappingRegistry.Register(path, method, handlerChain)
+	requestMapping.registerRequestHandler(path, RequestHandler{
+		Method:                method,
+		HandlerFunc:           handlerFunc,
+		requestObjectMetadata: metadata,
+	}, nil)
+}
+
+func (requestMapping RequestHandlerMapping) registerRequestHandler(path string, handler RequestHandler, controller Controller) {
+	handlerChain := NewHandlerChain(handler.HandlerFunc, requestMapping.interceptorRegistry, handler.requestObjectMetadata)
+	handlerChain.method = handler.Method
+	handlerChain.pattern = path
+	handlerChain.controller = controller
+	handlerChain.validateRequest = handler.validateRequest
+	handlerChain.securityRequirements = handler.securityRequirements
+	requestMapping.mappingRegistry.Register(path, handler.Method, handlerChain)
 }
 
 func (requestMapping RequestHandlerMapping) GetHandlerChain(ctx *WebRequestContext) {
@@ -0,0 +1,278 @@
+package web
+
+import (
+	"errors"
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/tr"
+	ut "github.com/go-playground/universal-translator"
+	"gopkg.in/go-playground/validator.v9"
+	entranslations "gopkg.in/go-playground/validator.v9/translations/en"
+	trtranslations "gopkg.in/go-playground/validator.v9/translations/tr"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const DefaultLocale = "en"
+
+const (
+	validationMessageCodePrefix  = "validation."
+	validationDefaultMessageCode = "validation.default"
+	validationFailedMessageCode  = "validation.failed"
+	bindingFailedMessageCode     = "binding.failed"
+	statusMessageCodePrefix      = "http.status."
+)
+
+var localizedStatusCodes = []int{
+	http.StatusNoContent,
+	http.StatusBadRequest,
+	http.StatusUnauthorized,
+	http.StatusForbidden,
+	http.StatusNotFound,
+	http.StatusMethodNotAllowed,
+	http.StatusRequestTimeout,
+	http.StatusConflict,
+	http.StatusRequestEntityTooLarge,
+	http.StatusUnsupportedMediaType,
+	http.StatusUnprocessableEntity,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+}
+
+var validationTranslations = map[string]func(validate *validator.Validate, translator ut.Translator) error{
+	"en": entranslations.RegisterDefaultTranslations,
+	"tr": trtranslations.RegisterDefaultTranslations,
+}
+
+var defaultMessages = map[string]map[string]string{
+	"tr": {
+		"validation.default": "{0} alanı '{1}' kuralını sağlamıyor",
+		"validation.failed":  "Doğrulama başarısız oldu",
+		"binding.failed":     "İstek bağlanamadı",
+		"http.status.204":    "İçerik Yok",
+		"http.status.400":    "Geçersiz İstek",
+		"http.status.401":    "Yetkisiz",
+		"http.status.403":    "Yasak",
+		"http.status.404":    "Bulunamadı",
+		"http.status.405":    "İzin Verilmeyen Yöntem",
+		"http.status.408":    "İstek Zaman Aşımı",
+		"http.status.409":    "Çakışma",
+		"http.status.413":    "İstek Gövdesi Çok Büyük",
+		"http.status.415":    "Desteklenmeyen Ortam Türü",
+		"http.status.422":    "İşlenemeyen Varlık",
+		"http.status.429":    "Çok Fazla İstek",
+		"http.status.500":    "Sunucu Hatası",
+		"http.status.502":    "Hatalı Ağ Geçidi",
+		"http.status.503":    "Hizmet Kullanılamıyor",
+	},
+	"de": {
+		"validation.required": "{0} ist ein Pflichtfeld",
+		"validation.min":      "{0} muss mindestens {1} sein",
+		"validation.max":      "{0} darf höchstens {1} sein",
+		"validation.len":      "{0} muss eine Länge von {1} haben",
+		"validation.eq":       "{0} muss gleich {1} sein",
+		"validation.ne":       "{0} darf nicht gleich {1} sein",
+		"validation.gt":       "{0} muss größer als {1} sein",
+		"validation.gte":      "{0} muss größer oder gleich {1} sein",
+		"validation.lt":       "{0} muss kleiner als {1} sein",
+		"validation.lte":      "{0} muss kleiner oder gleich {1} sein",
+		"validation.oneof":    "{0} muss einer der folgenden Werte sein: [{1}]",
+		"validation.email":    "{0} muss eine gültige E-Mail-Adresse sein",
+		"validation.url":      "{0} muss eine gültige URL sein",
+		"validation.uuid":     "{0} muss eine gültige UUID sein",
+		"validation.alpha":    "{0} darf nur Buchstaben enthalten",
+		"validation.alphanum": "{0} darf nur Buchstaben und Ziffern enthalten",
+		"validation.numeric":  "{0} muss eine gültige Zahl sein",
+		"validation.default":  "{0} hat die Regel '{1}' nicht erfüllt",
+		"validation.failed":   "Validierung fehlgeschlagen",
+		"binding.failed":      "Anfrage konnte nicht gebunden werden",
+		"http.status.204":     "Kein Inhalt",
+		"http.status.400":     "Ungültige Anfrage",
+		"http.status.401":     "Nicht autorisiert",
+		"http.status.403":     "Verboten",
+		"http.status.404":     "Nicht gefunden",
+		"http.status.405":     "Methode nicht erlaubt",
+		"http.status.408":     "Zeitüberschreitung der Anfrage",
+		"http.status.409":     "Konflikt",
+		"http.status.413":     "Anfrage zu groß",
+		"http.status.415":     "Nicht unterstützter Medientyp",
+		"http.status.422":     "Nicht verarbeitbare Entität",
+		"http.status.429":     "Zu viele Anfragen",
+		"http.status.500":     "Interner Serverfehler",
+		"http.status.502":     "Fehlerhaftes Gateway",
+		"http.status.503":     "Dienst nicht verfügbar",
+	},
+}
+
+func englishMessages() map[string]string {
+	messages := map[string]string{
+		validationDefaultMessageCode: defaultValidationMessage,
+		validationFailedMessageCode:  "Validation failed",
+		bindingFailedMessageCode:     "Request could not be bound",
+	}
+
+	for _, code := range localizedStatusCodes {
+		messages[statusMessageCode(code)] = http.StatusText(code)
+	}
+
+	return messages
+}
+
+func statusMessageCode(code int) string {
+	return statusMessageCodePrefix + strconv.Itoa(code)
+}
+
+type MessageSource interface {
+	GetMessage(locale string, code string, args ...string) (string, bool)
+}
+
+type TranslatorMessageSource struct {
+	translator   *ut.UniversalTranslator
+	mu           sync.RWMutex
+	validate     *validator.Validate
+	validateOnce sync.Once
+}
+
+func NewTranslatorMessageSource() *TranslatorMessageSource {
+	english := en.New()
+	source := &TranslatorMessageSource{
+		translator: ut.New(english, english, tr.New(), de.New()),
+	}
+
+	_ = source.AddMessages(DefaultLocale, englishMessages())
+	for locale, messages := range defaultMessages {
+		_ = source.AddMessages(locale, messages)
+	}
+
+	return source
+}
+
+func (source *TranslatorMessageSource) AddLocale(translator locales.Translator) error {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	return source.translator.AddTranslator(translator, false)
+}
+
+func (source *TranslatorMessageSource) AddMessages(locale string, messages map[string]string) error {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	translator, found := source.translator.GetTranslator(translatorLocale(locale))
+	if !found {
+		return errors.New("locale is not supported : " + locale)
+	}
+
+	for code, message := range messages {
+		if err := translator.Add(code, message, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (source *TranslatorMessageSource) GetMessage(locale string, code string, args ...string) (string, bool) {
+	source.mu.RLock()
+	defer source.mu.RUnlock()
+
+	for _, translator := range source.getTranslators(locale) {
+		if message, ok := translate(translator, code, args...); ok {
+			return message, true
+		}
+	}
+
+	return translate(source.translator.GetFallback(), code, args...)
+}
+
+func (source *TranslatorMessageSource) getTranslators(locale string) []ut.Translator {
+	locale = translatorLocale(locale)
+	candidates := []string{locale}
+	if index := strings.Index(locale, "_"); index != -1 {
+		candidates = append(candidates, locale[:index])
+	}
+
+	translators := make([]ut.Translator, 0, len(candidates))
+	for _, candidate := range candidates {
+		if translator, found := source.translator.GetTranslator(candidate); found {
+			translators = append(translators, translator)
+		}
+	}
+	return translators
+}
+
+func (source *TranslatorMessageSource) getValidate() *validator.Validate {
+	source.validateOnce.Do(func() {
+		source.mu.Lock()
+		defer source.mu.Unlock()
+
+		source.validate = newValidate()
+		for locale, registerTranslations := range validationTranslations {
+			translator, found := source.translator.GetTranslator(locale)
+			if !found {
+				continue
+			}
+
+			if err := registerTranslations(source.validate, translator); err != nil {
+				panic("Validation translations could not be registered : " + err.Error())
+			}
+		}
+	})
+	return source.validate
+}
+
+func (source *TranslatorMessageSource) translateFieldError(locale string, fieldError validator.FieldError) (string, bool) {
+	source.mu.RLock()
+	defer source.mu.RUnlock()
+
+	translators := source.getTranslators(locale)
+	if len(translators) == 0 {
+		translators = append(translators, source.translator.GetFallback())
+	}
+
+	var untranslated string
+	if err, ok := fieldError.(error); ok {
+		untranslated = err.Error()
+	}
+
+	for _, translator := range translators {
+		if message := fieldError.Translate(translator); message != untranslated {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+var (
+	sharedMessageSource     *TranslatorMessageSource
+	sharedMessageSourceOnce sync.Once
+)
+
+func defaultMessageSource() *TranslatorMessageSource {
+	sharedMessageSourceOnce.Do(func() {
+		sharedMessageSource = NewTranslatorMessageSource()
+	})
+	return sharedMessageSource
+}
+
+func translate(translator ut.Translator, code string, args ...string) (message string, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			message, ok = "", false
+		}
+	}()
+
+	message, err := translator.T(code, args...)
+	if err != nil {
+		return "", false
+	}
+	return message, true
+}
+
+func translatorLocale(locale string) string {
+	return strings.ToLower(strings.Replace(locale, "-", "_", -1))
+}
@@ -0,0 +1,409 @@
+package web
+
+import (
+	"bytes"
+	core "github.com/procyon-projects/procyon-core"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	metricsStartTimeKey = "procyon.web.metrics-start-time"
+	metricMethodOther   = "OTHER"
+)
+
+var DefaultMetricsBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+var metricMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+type metricLabels struct {
+	method string
+	route  string
+	status string
+}
+
+func (labels metricLabels) format(names ...string) string {
+	var buffer strings.Builder
+	buffer.WriteByte('{')
+	for index, name := range names {
+		if index != 0 {
+			buffer.WriteByte(',')
+		}
+
+		var value string
+		switch name {
+		case "method":
+			value = labels.method
+		case "route":
+			value = labels.route
+		case "status":
+			value = labels.status
+		}
+
+		buffer.WriteString(name)
+		buffer.WriteString("=\"")
+		buffer.WriteString(escapeLabelValue(value))
+		buffer.WriteByte('"')
+	}
+	buffer.WriteByte('}')
+	return buffer.String()
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type summary struct {
+	sum   float64
+	count uint64
+}
+
+type HTTPMetrics struct {
+	enabled       bool
+	buckets       []float64
+	requests      map[metricLabels]uint64
+	durations     map[metricLabels]*histogram
+	inFlight      map[metricLabels]int64
+	requestSizes  map[metricLabels]*summary
+	responseSizes map[metricLabels]*summary
+	mu            sync.Mutex
+}
+
+func NewHTTPMetrics(properties *MetricsProperties) *HTTPMetrics {
+	enabled := true
+	buckets := DefaultMetricsBuckets
+	if properties != nil {
+		enabled = properties.Enabled
+		if properties.Buckets != "" {
+			buckets = parseMetricsBuckets(properties.Buckets)
+		}
+	}
+
+	return &HTTPMetrics{
+		enabled:       enabled,
+		buckets:       buckets,
+		requests:      make(map[metricLabels]uint64),
+		durations:     make(map[metricLabels]*histogram),
+		inFlight:      make(map[metricLabels]int64),
+		requestSizes:  make(map[metricLabels]*summary),
+		responseSizes: make(map[metricLabels]*summary),
+	}
+}
+
+func parseMetricsBuckets(value string) []float64 {
+	buckets := make([]float64, 0)
+	for _, bucket := range strings.Split(value, ",") {
+		bucket = strings.TrimSpace(bucket)
+		if bucket == "" {
+			continue
+		}
+
+		upperBound, err := strconv.ParseFloat(bucket, 64)
+		if err != nil {
+			panic("Invalid metrics bucket : " + bucket)
+		}
+		buckets = append(buckets, upperBound)
+	}
+
+	if len(buckets) == 0 {
+		return DefaultMetricsBuckets
+	}
+
+	sort.Float64s(buckets)
+	return buckets
+}
+
+func (metrics *HTTPMetrics) RequestStarted(method string, route string) {
+	if !metrics.enabled {
+		return
+	}
+
+	metrics.mu.Lock()
+	metrics.inFlight[metricLabels{method: method, route: route}]++
+	metrics.mu.Unlock()
+}
+
+func (metrics *HTTPMetrics) RequestCompleted(method string, route string, status int, duration time.Duration, requestSize int, responseSize int) {
+	if !metrics.enabled {
+		return
+	}
+
+	labels := metricLabels{method: method, route: route}
+	statusLabels := metricLabels{method: method, route: route, status: statusClass(status)}
+	seconds := duration.Seconds()
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	if metrics.inFlight[labels] > 0 {
+		metrics.inFlight[labels]--
+	}
+	metrics.requests[statusLabels]++
+
+	durationHistogram, ok := metrics.durations[statusLabels]
+	if !ok {
+		durationHistogram = &histogram{
+			counts: make([]uint64, len(metrics.buckets)),
+		}
+		metrics.durations[statusLabels] = durationHistogram
+	}
+
+	for index, upperBound := range metrics.buckets {
+		if seconds <= upperBound {
+			durationHistogram.counts[index]++
+		}
+	}
+	durationHistogram.sum += seconds
+	durationHistogram.count++
+
+	observeSummary(metrics.requestSizes, labels, float64(requestSize))
+	if responseSize >= 0 {
+		observeSummary(metrics.responseSizes, statusLabels, float64(responseSize))
+	}
+}
+
+func observeSummary(summaries map[metricLabels]*summary, labels metricLabels, value float64) {
+	sizeSummary, ok := summaries[labels]
+	if !ok {
+		sizeSummary = &summary{}
+		summaries[labels] = sizeSummary
+	}
+
+	sizeSummary.sum += value
+	sizeSummary.count++
+}
+
+func (metrics *HTTPMetrics) WriteMetrics(writer io.Writer) error {
+	var buffer bytes.Buffer
+
+	metrics.mu.Lock()
+
+	writeMetricHeader(&buffer, "http_requests_total", "counter", "Total number of HTTP requests.")
+	for _, labels := range sortedLabels(metrics.requests) {
+		writeSample(&buffer, "http_requests_total", labels.format("method", "route", "status"), float64(metrics.requests[labels]))
+	}
+
+	writeMetricHeader(&buffer, "http_request_duration_seconds", "histogram", "HTTP request latency in seconds.")
+	for _, labels := range sortedLabels(metrics.durations) {
+		durationHistogram := metrics.durations[labels]
+		for index, upperBound := range metrics.buckets {
+			bucketLabels := strings.TrimSuffix(labels.format("method", "route", "status"), "}") + ",le=\"" + formatFloat(upperBound) + "\"}"
+			writeSample(&buffer, "http_request_duration_seconds_bucket", bucketLabels, float64(durationHistogram.counts[index]))
+		}
+		infLabels := strings.TrimSuffix(labels.format("method", "route", "status"), "}") + ",le=\"+Inf\"}"
+		writeSample(&buffer, "http_request_duration_seconds_bucket", infLabels, float64(durationHistogram.count))
+		writeSample(&buffer, "http_request_duration_seconds_sum", labels.format("method", "route", "status"), durationHistogram.sum)
+		writeSample(&buffer, "http_request_duration_seconds_count", labels.format("method", "route", "status"), float64(durationHistogram.count))
+	}
+
+	writeMetricHeader(&buffer, "http_requests_in_flight", "gauge", "Number of HTTP requests currently being served.")
+	for _, labels := range sortedLabels(metrics.inFlight) {
+		writeSample(&buffer, "http_requests_in_flight", labels.format("method", "route"), float64(metrics.inFlight[labels]))
+	}
+
+	writeMetricHeader(&buffer, "http_request_size_bytes", "summary", "HTTP request body size in bytes.")
+	for _, labels := range sortedLabels(metrics.requestSizes) {
+		writeSummary(&buffer, "http_request_size_bytes", labels.format("method", "route"), metrics.requestSizes[labels])
+	}
+
+	writeMetricHeader(&buffer, "http_response_size_bytes", "summary", "HTTP response body size in bytes.")
+	for _, labels := range sortedLabels(metrics.responseSizes) {
+		writeSummary(&buffer, "http_response_size_bytes", labels.format("method", "route", "status"), metrics.responseSizes[labels])
+	}
+
+	metrics.mu.Unlock()
+
+	_, err := writer.Write(buffer.Bytes())
+	return err
+}
+
+func sortedLabels(samples interface{}) []metricLabels {
+	labels := make([]metricLabels, 0)
+	switch samples := samples.(type) {
+	case map[metricLabels]uint64:
+		for key := range samples {
+			labels = append(labels, key)
+		}
+	case map[metricLabels]int64:
+		for key := range samples {
+			labels = append(labels, key)
+		}
+	case map[metricLabels]*histogram:
+		for key := range samples {
+			labels = append(labels, key)
+		}
+	case map[metricLabels]*summary:
+		for key := range samples {
+			labels = append(labels, key)
+		}
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].route != labels[j].route {
+			return labels[i].route < labels[j].route
+		}
+		if labels[i].method != labels[j].method {
+			return labels[i].method < labels[j].method
+		}
+		return labels[i].status < labels[j].status
+	})
+	return labels
+}
+
+func writeMetricHeader(buffer *bytes.Buffer, name string, metricType string, help string) {
+	buffer.WriteString("# HELP " + name + " " + help + "\n")
+	buffer.WriteString("# TYPE " + name + " " + metricType + "\n")
+}
+
+func writeSample(buffer *bytes.Buffer, name string, labels string, value float64) {
+	buffer.WriteString(name + labels + " " + formatFloat(value) + "\n")
+}
+
+func writeSummary(buffer *bytes.Buffer, name string, labels string, sizeSummary *summary) {
+	writeSample(buffer, name+"_sum", labels, sizeSummary.sum)
+	writeSample(buffer, name+"_count", labels, float64(sizeSummary.count))
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func escapeLabelValue(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "\n", "\\n")
+	return strings.ReplaceAll(value, "\"", "\\\"")
+}
+
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+type MetricsInterceptor struct {
+	metrics *HTTPMetrics
+}
+
+func NewMetricsInterceptor(metrics *HTTPMetrics) MetricsInterceptor {
+	if metrics == nil {
+		panic("Metrics must not be null")
+	}
+
+	return MetricsInterceptor{
+		metrics,
+	}
+}
+
+func (interceptor MetricsInterceptor) GetPriority() core.PriorityValue {
+	return core.PriorityHighest
+}
+
+func (interceptor MetricsInterceptor) InterceptsUnmatchedRequests() bool {
+	return true
+}
+
+func (interceptor MetricsInterceptor) HandleBefore(ctx *WebRequestContext) {
+	ctx.Put(metricsStartTimeKey, time.Now())
+	interceptor.metrics.RequestStarted(metricMethod(ctx), ctx.handlerChain.GetPattern())
+}
+
+func (interceptor MetricsInterceptor) AfterCompletion(ctx *WebRequestContext) {
+	startTime, ok := ctx.Get(metricsStartTimeKey).(time.Time)
+	if !ok {
+		return
+	}
+
+	response := &ctx.fastHttpRequestContext.Response
+	responseSize := -1
+	if !response.IsBodyStream() {
+		responseSize = len(response.Body())
+	}
+
+	interceptor.metrics.RequestCompleted(
+		metricMethod(ctx),
+		ctx.handlerChain.GetPattern(),
+		response.StatusCode(),
+		time.Since(startTime),
+		len(ctx.fastHttpRequestContext.Request.Body()),
+		responseSize,
+	)
+}
+
+func metricMethod(ctx *WebRequestContext) string {
+	if ctx.handlerChain.GetPattern() != "" {
+		return string(ctx.handlerChain.GetMethod())
+	}
+
+	method := string(ctx.fastHttpRequestContext.Method())
+	if !metricMethods[method] {
+		return metricMethodOther
+	}
+	return method
+}
+
+type MetricsController struct {
+	metrics    *HTTPMetrics
+	properties *MetricsProperties
+}
+
+func NewMetricsController(metrics *HTTPMetrics, properties *MetricsProperties) MetricsController {
+	if metrics == nil {
+		panic("Metrics must not be null")
+	}
+
+	if properties == nil {
+		properties = &MetricsProperties{
+			Enabled: true,
+		}
+	}
+
+	return MetricsController{
+		metrics,
+		properties,
+	}
+}
+
+func (controller MetricsController) RegisterManagementHandlers(registry HandlerRegistry) {
+	if !controller.properties.Enabled {
+		return
+	}
+
+	registry.Register(Get(controller.scrape, Path(pathOrDefault(controller.properties.Path, "/metrics"))))
+}
+
+func (controller MetricsController) scrape(ctx *WebRequestContext) {
+	var buffer bytes.Buffer
+	if err := controller.metrics.WriteMetrics(&buffer); err != nil {
+		panic(err)
+	}
+
+	ctx.fastHttpRequestContext.SetStatusCode(http.StatusOK)
+	ctx.fastHttpRequestContext.SetContentType(MetricsContentType)
+	ctx.fastHttpRequestContext.SetBody(buffer.Bytes())
+	ctx.responseEntity.status = http.StatusOK
+	ctx.responseWritten = true
+}
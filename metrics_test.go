@@ -0,0 +1,80 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type testMetricsController struct {
+}
+
+func (controller testMetricsController) RegisterHandlers(registry HandlerRegistry) {
+	registry.Register(Get(controller.fail, Path("/fail")))
+}
+
+func (controller testMetricsController) fail(ctx *WebRequestContext) {
+	panic("unexpected failure")
+}
+
+func TestMetricsInterceptor(t *testing.T) {
+	metrics := NewHTTPMetrics(&MetricsProperties{Enabled: true, Buckets: "0.5, 0.1"})
+	router := NewRouter(
+		WithControllers(testNetHttpController{}, testMetricsController{}),
+		WithManagementControllers(NewMetricsController(metrics, nil)),
+		WithInterceptors(NewMetricsInterceptor(metrics)),
+	)
+
+	serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", nil, "")
+	serveTestAdapterRequest(router, http.MethodGet, "/greetings/web", nil, "")
+	serveTestAdapterRequest(router, http.MethodPost, "/echo", nil, "payload")
+	serveTestAdapterRequest(router, http.MethodGet, "/fail", nil, "")
+	serveTestAdapterRequest(router, http.MethodGet, "/unknown", nil, "")
+	serveTestAdapterRequest(router, "PROPFIND", "/unknown", nil, "")
+	serveTestAdapterRequest(router, "RANDOM-1", "/unknown", nil, "")
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/metrics", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.Equal(t, MetricsContentType, string(response.Header.ContentType()))
+
+	body := string(response.Body())
+	assert.Contains(t, body, "# TYPE http_requests_total counter\n")
+	assert.Contains(t, body, "http_requests_total{method=\"GET\",route=\"/greetings/:name\",status=\"2xx\"} 2\n")
+	assert.Contains(t, body, "http_requests_total{method=\"POST\",route=\"/echo\",status=\"2xx\"} 1\n")
+	assert.Contains(t, body, "http_requests_total{method=\"GET\",route=\"/fail\",status=\"5xx\"} 1\n")
+	assert.NotContains(t, body, "/greetings/procyon")
+	assert.Contains(t, body, "http_requests_total{method=\"GET\",route=\"\",status=\"4xx\"} 1\n")
+	assert.Contains(t, body, "http_requests_in_flight{method=\"GET\",route=\"\"} 0\n")
+	assert.Contains(t, body, "http_requests_total{method=\"OTHER\",route=\"\",status=\"4xx\"} 2\n")
+	assert.NotContains(t, body, "PROPFIND")
+	assert.NotContains(t, body, "RANDOM-1")
+	assert.NotContains(t, body, "/unknown")
+
+	assert.Contains(t, body, "# TYPE http_request_duration_seconds histogram\n")
+	assert.Contains(t, body, "http_request_duration_seconds_bucket{method=\"GET\",route=\"/greetings/:name\",status=\"2xx\",le=\"0.1\"} 2\n")
+	assert.Contains(t, body, "http_request_duration_seconds_bucket{method=\"GET\",route=\"/greetings/:name\",status=\"2xx\",le=\"0.5\"} 2\n")
+	assert.Contains(t, body, "http_request_duration_seconds_bucket{method=\"GET\",route=\"/greetings/:name\",status=\"2xx\",le=\"+Inf\"} 2\n")
+	assert.Contains(t, body, "http_request_duration_seconds_count{method=\"GET\",route=\"/greetings/:name\",status=\"2xx\"} 2\n")
+
+	assert.Contains(t, body, "http_requests_in_flight{method=\"GET\",route=\"/fail\"} 0\n")
+	assert.Contains(t, body, "http_requests_in_flight{method=\"GET\",route=\"/metrics\"} 1\n")
+
+	assert.Contains(t, body, "http_request_size_bytes_sum{method=\"POST\",route=\"/echo\"} 7\n")
+	assert.Contains(t, body, "http_request_size_bytes_count{method=\"POST\",route=\"/echo\"} 1\n")
+	assert.Contains(t, body, "http_response_size_bytes_sum{method=\"POST\",route=\"/echo\",status=\"2xx\"} 8\n")
+}
+
+func TestHTTPMetrics_Disabled(t *testing.T) {
+	metrics := NewHTTPMetrics(&MetricsProperties{Enabled: false})
+	metrics.RequestStarted(http.MethodGet, "/users/:id")
+	metrics.RequestCompleted(http.MethodGet, "/users/:id", http.StatusOK, 0, 0, 0)
+
+	assert.Empty(t, metrics.requests)
+	assert.Empty(t, metrics.inFlight)
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	assert.Equal(t, "a\\\"b\\\\c\\nd", escapeLabelValue("a\"b\\c\nd"))
+	assert.Equal(t, "unknown", statusClass(0))
+	assert.Equal(t, "4xx", statusClass(http.StatusNotFound))
+}
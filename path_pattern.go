@@ -0,0 +1,36 @@
+package web
+
+import (
+	"path"
+	"strings"
+)
+
+func matchPathPattern(pattern string, requestPath string) bool {
+	return matchPathSegments(strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(strings.Trim(requestPath, "/"), "/"))
+}
+
+func matchPathSegments(patternSegments []string, pathSegments []string) bool {
+	for len(patternSegments) != 0 {
+		if patternSegments[0] == "**" {
+			for index := 0; index <= len(pathSegments); index++ {
+				if matchPathSegments(patternSegments[1:], pathSegments[index:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(pathSegments) == 0 {
+			return false
+		}
+
+		matched, err := path.Match(patternSegments[0], pathSegments[0])
+		if err != nil || !matched {
+			return false
+		}
+
+		patternSegments = patternSegments[1:]
+		pathSegments = pathSegments[1:]
+	}
+	return len(pathSegments) == 0
+}
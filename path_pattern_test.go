@@ -0,0 +1,17 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMatchPathPattern(t *testing.T) {
+	assert.True(t, matchPathPattern("/health", "/health"))
+	assert.True(t, matchPathPattern("/health/**", "/health"))
+	assert.True(t, matchPathPattern("/health/**", "/health/liveness"))
+	assert.True(t, matchPathPattern("/**/*.js", "/static/js/app.js"))
+	assert.True(t, matchPathPattern("/greetings/*", "/greetings/procyon"))
+	assert.False(t, matchPathPattern("/greetings/*", "/greetings/procyon/web"))
+	assert.False(t, matchPathPattern("/health", "/healthz"))
+	assert.False(t, matchPathPattern("/metrics/**", "/health"))
+}
@@ -0,0 +1,120 @@
+package web
+
+import (
+	"bytes"
+	json "github.com/json-iterator/go"
+	"net/http"
+	"sort"
+)
+
+const DefaultProblemType = "about:blank"
+
+type ProblemError struct {
+	Source string `json:"source,omitempty"`
+	Field  string `json:"field,omitempty"`
+	Rule   string `json:"rule,omitempty"`
+	Param  string `json:"param,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type ProblemDetail struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+func NewProblemDetail(httpError *HTTPError) *ProblemDetail {
+	problemDetail := &ProblemDetail{
+		Type:       httpError.Type,
+		Title:      httpError.Title,
+		Status:     httpError.Code,
+		Detail:     httpError.Detail,
+		Instance:   httpError.Instance,
+		Extensions: make(map[string]interface{}, len(httpError.Extensions)),
+	}
+
+	if problemDetail.Type == "" {
+		problemDetail.Type = DefaultProblemType
+	}
+
+	if problemDetail.Title == "" {
+		problemDetail.Title = http.StatusText(httpError.Code)
+	}
+
+	if message, ok := httpError.Message.(string); ok && problemDetail.Detail == "" && message != http.StatusText(httpError.Code) {
+		problemDetail.Detail = message
+	}
+
+	for key, value := range httpError.Extensions {
+		problemDetail.Extensions[key] = value
+	}
+
+	return problemDetail
+}
+
+func (problemDetail *ProblemDetail) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+
+	members := []struct {
+		name  string
+		value interface{}
+		omit  bool
+	}{
+		{"type", problemDetail.Type, false},
+		{"title", problemDetail.Title, problemDetail.Title == ""},
+		{"status", problemDetail.Status, problemDetail.Status == 0},
+		{"detail", problemDetail.Detail, problemDetail.Detail == ""},
+		{"instance", problemDetail.Instance, problemDetail.Instance == ""},
+	}
+
+	first := true
+	writeMember := func(name string, value interface{}) error {
+		encodedValue, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		if !first {
+			buffer.WriteByte(',')
+		}
+		first = false
+
+		encodedName, _ := json.Marshal(name)
+		buffer.Write(encodedName)
+		buffer.WriteByte(':')
+		buffer.Write(encodedValue)
+		return nil
+	}
+
+	for _, member := range members {
+		if member.omit {
+			continue
+		}
+		if err := writeMember(member.name, member.value); err != nil {
+			return nil, err
+		}
+	}
+
+	extensionKeys := make([]string, 0, len(problemDetail.Extensions))
+	for key := range problemDetail.Extensions {
+		switch key {
+		case "type", "title", "status", "detail", "instance":
+			continue
+		}
+		extensionKeys = append(extensionKeys, key)
+	}
+	sort.Strings(extensionKeys)
+
+	for _, key := range extensionKeys {
+		if err := writeMember(key, problemDetail.Extensions[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
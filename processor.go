@@ -17,7 +17,7 @@ func (processor RequestHandlerMappingProcessor) BeforePeaInitialization(peaName
 	if controller, ok := pea.(Controller); ok {
 		handlerRegistry := NewSimpleHandlerRegistry()
 		controller.RegisterHandlers(handlerRegistry)
-		processor.processHandler(handlerRegistry)
+		processor.processHandler(controller, handlerRegistry)
 	}
 	return pea, nil
 }
@@ -26,12 +26,12 @@ func (processor RequestHandlerMappingProcessor) AfterPeaInitialization(peaName s
 	return pea, nil
 }
 
-func (processor RequestHandlerMappingProcessor) processHandler(handlerRegistry HandlerRegistry) {
+func (processor RequestHandlerMappingProcessor) processHandler(controller Controller, handlerRegistry HandlerRegistry) {
 	if simpleRegistry, ok := handlerRegistry.(SimpleHandlerRegistry); ok {
 		registryMap := simpleRegistry.getRegistryMap()
 		for prefix, handlers := range registryMap {
 			for _, handler := range handlers {
-				processor.requestHandlerMapping.RegisterHandlerMethod(prefix+handler.Path, handler.Method, handler.HandlerFunc, handler.requestObjectMetadata)
+				processor.requestHandlerMapping.registerRequestHandler(prefix+handler.Path, handler, controller)
 			}
 		}
 	}
@@ -52,7 +52,7 @@ func (processor HandlerInterceptorProcessor) BeforePeaInitialization(peaName str
 		return nil, nil
 	}
 
-	if processor.interceptorRegistry != nil {
+	if processor.interceptorRegistry != nil && !isManagementInterceptor(pea) {
 		processor.interceptorRegistry.RegisterHandlerInterceptor(pea)
 	}
 	return pea, nil
@@ -61,3 +61,136 @@ func (processor HandlerInterceptorProcessor) BeforePeaInitialization(peaName str
 func (processor HandlerInterceptorProcessor) AfterPeaInitialization(peaName string, pea interface{}) (interface{}, error) {
 	return pea, nil
 }
+
+type ErrorAdviceProcessor struct {
+	errorHandlerRegistry ErrorHandlerRegistry
+}
+
+func NewErrorAdviceProcessor(errorHandlerRegistry ErrorHandlerRegistry) ErrorAdviceProcessor {
+	return ErrorAdviceProcessor{
+		errorHandlerRegistry,
+	}
+}
+
+func (processor ErrorAdviceProcessor) BeforePeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	if pea == nil {
+		return nil, nil
+	}
+
+	if advice, ok := pea.(ErrorAdvice); ok && processor.errorHandlerRegistry != nil {
+		advice.RegisterErrorHandlers(processor.errorHandlerRegistry)
+	}
+	return pea, nil
+}
+
+func (processor ErrorAdviceProcessor) AfterPeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	return pea, nil
+}
+
+type HealthIndicatorProcessor struct {
+	healthIndicatorRegistry HealthIndicatorRegistry
+}
+
+func NewHealthIndicatorProcessor(healthIndicatorRegistry HealthIndicatorRegistry) HealthIndicatorProcessor {
+	return HealthIndicatorProcessor{
+		healthIndicatorRegistry,
+	}
+}
+
+func (processor HealthIndicatorProcessor) BeforePeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	if pea == nil {
+		return nil, nil
+	}
+
+	if indicator, ok := pea.(HealthIndicator); ok && processor.healthIndicatorRegistry != nil {
+		processor.healthIndicatorRegistry.RegisterHealthIndicator(indicator)
+	}
+	return pea, nil
+}
+
+func (processor HealthIndicatorProcessor) AfterPeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	return pea, nil
+}
+
+type ManagementProcessor struct {
+	managementRegistry ManagementRegistry
+}
+
+func NewManagementProcessor(managementRegistry ManagementRegistry) ManagementProcessor {
+	return ManagementProcessor{
+		managementRegistry,
+	}
+}
+
+func (processor ManagementProcessor) BeforePeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	if pea == nil {
+		return nil, nil
+	}
+
+	if processor.managementRegistry == nil {
+		return pea, nil
+	}
+
+	if controller, ok := pea.(ManagementController); ok {
+		processor.managementRegistry.RegisterManagementController(controller)
+	}
+
+	if isManagementInterceptor(pea) {
+		processor.managementRegistry.RegisterManagementInterceptor(pea)
+	}
+	return pea, nil
+}
+
+func (processor ManagementProcessor) AfterPeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	return pea, nil
+}
+
+type AuthenticatorProcessor struct {
+	authenticatorRegistry AuthenticatorRegistry
+}
+
+func NewAuthenticatorProcessor(authenticatorRegistry AuthenticatorRegistry) AuthenticatorProcessor {
+	return AuthenticatorProcessor{
+		authenticatorRegistry,
+	}
+}
+
+func (processor AuthenticatorProcessor) BeforePeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	if pea == nil {
+		return nil, nil
+	}
+
+	if authenticator, ok := pea.(Authenticator); ok && processor.authenticatorRegistry != nil {
+		processor.authenticatorRegistry.RegisterAuthenticator(authenticator)
+	}
+	return pea, nil
+}
+
+func (processor AuthenticatorProcessor) AfterPeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	return pea, nil
+}
+
+type SecurityConfigurerProcessor struct {
+	securityRuleRegistry SecurityRuleRegistry
+}
+
+func NewSecurityConfigurerProcessor(securityRuleRegistry SecurityRuleRegistry) SecurityConfigurerProcessor {
+	return SecurityConfigurerProcessor{
+		securityRuleRegistry,
+	}
+}
+
+func (processor SecurityConfigurerProcessor) BeforePeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	if pea == nil {
+		return nil, nil
+	}
+
+	if configurer, ok := pea.(SecurityConfigurer); ok && processor.securityRuleRegistry != nil {
+		configurer.ConfigureSecurity(processor.securityRuleRegistry)
+	}
+	return pea, nil
+}
+
+func (processor SecurityConfigurerProcessor) AfterPeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	return pea, nil
+}
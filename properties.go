@@ -0,0 +1,280 @@
+package web
+
+type ErrorProperties struct {
+	ProblemDetails        bool `yaml:"problem-details" json:"problem-details" default:"false"`
+	ValidationErrorStatus int  `yaml:"validation-error-status" json:"validation-error-status" default:"400"`
+}
+
+func newErrorProperties() *ErrorProperties {
+	return &ErrorProperties{}
+}
+
+func (properties *ErrorProperties) GetConfigurationPrefix() string {
+	return "server.error"
+}
+
+type LocaleProperties struct {
+	Default       string `yaml:"default" json:"default" default:"en"`
+	Supported     string `yaml:"supported" json:"supported" default:"en,tr,de"`
+	ParameterName string `yaml:"parameter-name" json:"parameter-name" default:"lang"`
+	CookieName    string `yaml:"cookie-name" json:"cookie-name" default:"lang"`
+}
+
+func newLocaleProperties() *LocaleProperties {
+	return &LocaleProperties{}
+}
+
+func (properties *LocaleProperties) GetConfigurationPrefix() string {
+	return "server.locale"
+}
+
+type ServerBackendProperties struct {
+	Type               string `yaml:"type" json:"type" default:"fasthttp"`
+	H2C                bool   `yaml:"h2c" json:"h2c" default:"false"`
+	TLSCertFile        string `yaml:"tls-cert-file" json:"tls-cert-file"`
+	TLSKeyFile         string `yaml:"tls-key-file" json:"tls-key-file"`
+	MaxRequestBodySize int    `yaml:"max-request-body-size" json:"max-request-body-size" default:"4194304"`
+}
+
+func newServerBackendProperties() *ServerBackendProperties {
+	return &ServerBackendProperties{}
+}
+
+func (properties *ServerBackendProperties) GetConfigurationPrefix() string {
+	return "server.backend"
+}
+
+type HealthProperties struct {
+	Enabled       bool   `yaml:"enabled" json:"enabled" default:"true"`
+	Path          string `yaml:"path" json:"path" default:"/health"`
+	LivenessPath  string `yaml:"liveness-path" json:"liveness-path" default:"/health/liveness"`
+	ReadinessPath string `yaml:"readiness-path" json:"readiness-path" default:"/health/readiness"`
+	ShowDetails   bool   `yaml:"show-details" json:"show-details" default:"true"`
+}
+
+func newHealthProperties() *HealthProperties {
+	return &HealthProperties{}
+}
+
+func (properties *HealthProperties) GetConfigurationPrefix() string {
+	return "server.health"
+}
+
+type ShutdownProperties struct {
+	ReadinessDrain uint `yaml:"readiness-drain" json:"readiness-drain" default:"0"`
+}
+
+func newShutdownProperties() *ShutdownProperties {
+	return &ShutdownProperties{}
+}
+
+func (properties *ShutdownProperties) GetConfigurationPrefix() string {
+	return "server.shutdown"
+}
+
+type ManagementServerProperties struct {
+	Port            uint `yaml:"port" json:"port" default:"8081"`
+	ServeOnMainPort bool `yaml:"serve-on-main-port" json:"serve-on-main-port" default:"false"`
+}
+
+func newManagementServerProperties() *ManagementServerProperties {
+	return &ManagementServerProperties{}
+}
+
+func (properties *ManagementServerProperties) GetConfigurationPrefix() string {
+	return "server.management"
+}
+
+type MetricsProperties struct {
+	Enabled bool   `yaml:"enabled" json:"enabled" default:"true"`
+	Path    string `yaml:"path" json:"path" default:"/metrics"`
+	Buckets string `yaml:"buckets" json:"buckets" default:"0.005,0.01,0.025,0.05,0.1,0.25,0.5,1,2.5,5,10"`
+}
+
+func newMetricsProperties() *MetricsProperties {
+	return &MetricsProperties{}
+}
+
+func (properties *MetricsProperties) GetConfigurationPrefix() string {
+	return "server.metrics"
+}
+
+type TracingProperties struct {
+	Exporter string `yaml:"exporter" json:"exporter" default:"none"`
+}
+
+func newTracingProperties() *TracingProperties {
+	return &TracingProperties{}
+}
+
+func (properties *TracingProperties) GetConfigurationPrefix() string {
+	return "server.tracing"
+}
+
+type RequestIdProperties struct {
+	Enabled bool   `yaml:"enabled" json:"enabled" default:"true"`
+	Header  string `yaml:"header" json:"header" default:"X-Request-ID"`
+	Echo    bool   `yaml:"echo" json:"echo" default:"true"`
+}
+
+func newRequestIdProperties() *RequestIdProperties {
+	return &RequestIdProperties{}
+}
+
+func (properties *RequestIdProperties) GetConfigurationPrefix() string {
+	return "server.request-id"
+}
+
+type AccessLogProperties struct {
+	Enabled    bool    `yaml:"enabled" json:"enabled" default:"false"`
+	Format     string  `yaml:"format" json:"format" default:"json"`
+	SampleRate float64 `yaml:"sample-rate" json:"sample-rate" default:"1"`
+	Exclude    string  `yaml:"exclude" json:"exclude"`
+}
+
+func newAccessLogProperties() *AccessLogProperties {
+	return &AccessLogProperties{}
+}
+
+func (properties *AccessLogProperties) GetConfigurationPrefix() string {
+	return "server.access-log"
+}
+
+type RateLimitProperties struct {
+	Enabled   bool   `yaml:"enabled" json:"enabled" default:"false"`
+	Algorithm string `yaml:"algorithm" json:"algorithm" default:"token-bucket"`
+	Limit     int    `yaml:"limit" json:"limit" default:"100"`
+	Period    int    `yaml:"period" json:"period" default:"60"`
+	Key       string `yaml:"key" json:"key" default:"ip"`
+}
+
+func newRateLimitProperties() *RateLimitProperties {
+	return &RateLimitProperties{}
+}
+
+func (properties *RateLimitProperties) GetConfigurationPrefix() string {
+	return "server.rate-limit"
+}
+
+type AuthenticationProperties struct {
+	Enabled  bool `yaml:"enabled" json:"enabled" default:"true"`
+	Required bool `yaml:"required" json:"required" default:"false"`
+}
+
+func newAuthenticationProperties() *AuthenticationProperties {
+	return &AuthenticationProperties{}
+}
+
+func (properties *AuthenticationProperties) GetConfigurationPrefix() string {
+	return "server.authentication"
+}
+
+type JWTProperties struct {
+	Enabled          bool   `yaml:"enabled" json:"enabled" default:"false"`
+	Secret           string `yaml:"secret" json:"secret"`
+	PublicKeyFile    string `yaml:"public-key-file" json:"public-key-file"`
+	JWKSFile         string `yaml:"jwks-file" json:"jwks-file"`
+	Issuer           string `yaml:"issuer" json:"issuer"`
+	Audience         string `yaml:"audience" json:"audience"`
+	ClockSkew        int    `yaml:"clock-skew" json:"clock-skew" default:"60"`
+	NameClaim        string `yaml:"name-claim" json:"name-claim" default:"sub"`
+	AuthoritiesClaim string `yaml:"authorities-claim" json:"authorities-claim" default:"scope"`
+}
+
+func newJWTProperties() *JWTProperties {
+	return &JWTProperties{}
+}
+
+func (properties *JWTProperties) GetConfigurationPrefix() string {
+	return "server.authentication.jwt"
+}
+
+type ForwardedProperties struct {
+	TrustedProxies string `yaml:"trusted-proxies" json:"trusted-proxies"`
+	Header         string `yaml:"header" json:"header" default:"x-forwarded-for"`
+}
+
+func newForwardedProperties() *ForwardedProperties {
+	return &ForwardedProperties{}
+}
+
+func (properties *ForwardedProperties) GetConfigurationPrefix() string {
+	return "server.forwarded"
+}
+
+type CSRFProperties struct {
+	Enabled      bool   `yaml:"enabled" json:"enabled" default:"false"`
+	Mode         string `yaml:"mode" json:"mode" default:"double-submit"`
+	CookieName   string `yaml:"cookie-name" json:"cookie-name" default:"XSRF-TOKEN"`
+	CookiePath   string `yaml:"cookie-path" json:"cookie-path" default:"/"`
+	CookieSecure bool   `yaml:"cookie-secure" json:"cookie-secure" default:"false"`
+	HeaderName   string `yaml:"header-name" json:"header-name" default:"X-XSRF-TOKEN"`
+	FieldName    string `yaml:"field-name" json:"field-name" default:"_csrf"`
+	Exempt       string `yaml:"exempt" json:"exempt"`
+}
+
+func newCSRFProperties() *CSRFProperties {
+	return &CSRFProperties{}
+}
+
+func (properties *CSRFProperties) GetConfigurationPrefix() string {
+	return "server.csrf"
+}
+
+type SessionProperties struct {
+	Enabled         bool   `yaml:"enabled" json:"enabled" default:"false"`
+	Store           string `yaml:"store" json:"store" default:"memory"`
+	Secret          string `yaml:"secret" json:"secret"`
+	CookieName      string `yaml:"cookie-name" json:"cookie-name" default:"SESSION"`
+	CookiePath      string `yaml:"cookie-path" json:"cookie-path" default:"/"`
+	CookieDomain    string `yaml:"cookie-domain" json:"cookie-domain"`
+	CookieSecure    bool   `yaml:"cookie-secure" json:"cookie-secure" default:"false"`
+	CookieHttpOnly  bool   `yaml:"cookie-http-only" json:"cookie-http-only" default:"true"`
+	CookieSameSite  string `yaml:"cookie-same-site" json:"cookie-same-site" default:"lax"`
+	IdleTimeout     int    `yaml:"idle-timeout" json:"idle-timeout" default:"1800"`
+	AbsoluteTimeout int    `yaml:"absolute-timeout" json:"absolute-timeout" default:"86400"`
+}
+
+func newSessionProperties() *SessionProperties {
+	return &SessionProperties{}
+}
+
+func (properties *SessionProperties) GetConfigurationPrefix() string {
+	return "server.session"
+}
+
+type SecurityHeadersProperties struct {
+	Enabled                   bool   `yaml:"enabled" json:"enabled" default:"false"`
+	HSTSMaxAge                int    `yaml:"hsts-max-age" json:"hsts-max-age" default:"31536000"`
+	HSTSIncludeSubDomains     bool   `yaml:"hsts-include-sub-domains" json:"hsts-include-sub-domains" default:"true"`
+	HSTSPreload               bool   `yaml:"hsts-preload" json:"hsts-preload" default:"false"`
+	ContentSecurityPolicy     string `yaml:"content-security-policy" json:"content-security-policy" default:"default-src 'self'"`
+	ContentTypeOptions        string `yaml:"content-type-options" json:"content-type-options" default:"nosniff"`
+	FrameOptions              string `yaml:"frame-options" json:"frame-options" default:"DENY"`
+	ReferrerPolicy            string `yaml:"referrer-policy" json:"referrer-policy" default:"no-referrer"`
+	PermissionsPolicy         string `yaml:"permissions-policy" json:"permissions-policy"`
+	CrossOriginOpenerPolicy   string `yaml:"cross-origin-opener-policy" json:"cross-origin-opener-policy" default:"same-origin"`
+	CrossOriginEmbedderPolicy string `yaml:"cross-origin-embedder-policy" json:"cross-origin-embedder-policy"`
+}
+
+func newSecurityHeadersProperties() *SecurityHeadersProperties {
+	return &SecurityHeadersProperties{}
+}
+
+func (properties *SecurityHeadersProperties) GetConfigurationPrefix() string {
+	return "server.security-headers"
+}
+
+type IPFilterProperties struct {
+	Enabled        bool   `yaml:"enabled" json:"enabled" default:"false"`
+	RulesFile      string `yaml:"rules-file" json:"rules-file"`
+	ReloadInterval uint   `yaml:"reload-interval" json:"reload-interval" default:"10"`
+}
+
+func newIPFilterProperties() *IPFilterProperties {
+	return &IPFilterProperties{}
+}
+
+func (properties *IPFilterProperties) GetConfigurationPrefix() string {
+	return "server.ip-filter"
+}
@@ -0,0 +1,378 @@
+package web
+
+import (
+	core "github.com/procyon-projects/procyon-core"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	HeaderRetryAfter         = "Retry-After"
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+)
+
+const (
+	RateLimitAlgorithmTokenBucket   = "token-bucket"
+	RateLimitAlgorithmSlidingWindow = "sliding-window"
+)
+
+const (
+	RateLimitKeyIP        = "ip"
+	RateLimitKeyPrincipal = "principal"
+	RateLimitKeyHeader    = "header:"
+)
+
+const RateLimitInterceptorPriority = core.PriorityHighest + 200
+
+const rateLimitSweepInterval = time.Minute
+
+type RateLimit struct {
+	Algorithm string
+	Limit     int
+	Period    time.Duration
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+type RateLimitState struct {
+	Tokens        float64
+	Count         int
+	PreviousCount int
+	Timestamp     time.Time
+}
+
+type RateLimitStore interface {
+	Update(key string, ttl time.Duration, update func(state *RateLimitState))
+}
+
+type rateLimitEntry struct {
+	state     RateLimitState
+	expiresAt time.Time
+}
+
+type InMemoryRateLimitStore struct {
+	entries   map[string]*rateLimitEntry
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+func NewInMemoryRateLimitStore() *InMemoryRateLimitStore {
+	return &InMemoryRateLimitStore{
+		entries:   make(map[string]*rateLimitEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+func (store *InMemoryRateLimitStore) Update(key string, ttl time.Duration, update func(state *RateLimitState)) {
+	now := time.Now()
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if now.Sub(store.lastSweep) >= rateLimitSweepInterval {
+		store.sweep(now)
+	}
+
+	entry, ok := store.entries[key]
+	if !ok || now.After(entry.expiresAt) {
+		entry = &rateLimitEntry{}
+		store.entries[key] = entry
+	}
+
+	update(&entry.state)
+	entry.expiresAt = now.Add(ttl)
+}
+
+func (store *InMemoryRateLimitStore) sweep(now time.Time) {
+	for key, entry := range store.entries {
+		if now.After(entry.expiresAt) {
+			delete(store.entries, key)
+		}
+	}
+	store.lastSweep = now
+}
+
+func (store *InMemoryRateLimitStore) Len() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return len(store.entries)
+}
+
+func (rateLimit RateLimit) validate() {
+	if rateLimit.Limit <= 0 || rateLimit.Period <= 0 {
+		panic("Rate limit and period must be positive")
+	}
+
+	switch rateLimit.Algorithm {
+	case RateLimitAlgorithmSlidingWindow, RateLimitAlgorithmTokenBucket, "":
+	default:
+		panic("Unsupported rate limit algorithm : " + rateLimit.Algorithm)
+	}
+}
+
+func (rateLimit RateLimit) Take(store RateLimitStore, key string, now time.Time) RateLimitResult {
+	rateLimit.validate()
+
+	var result RateLimitResult
+	if rateLimit.Algorithm == RateLimitAlgorithmSlidingWindow {
+		store.Update(key, 2*rateLimit.Period, func(state *RateLimitState) {
+			result = rateLimit.takeSlidingWindow(state, now)
+		})
+	} else {
+		store.Update(key, rateLimit.Period, func(state *RateLimitState) {
+			result = rateLimit.takeTokenBucket(state, now)
+		})
+	}
+	return result
+}
+
+func (rateLimit RateLimit) takeTokenBucket(state *RateLimitState, now time.Time) RateLimitResult {
+	capacity := float64(rateLimit.Limit)
+	refillRate := capacity / rateLimit.Period.Seconds()
+
+	if state.Timestamp.IsZero() {
+		state.Tokens = capacity
+	} else if elapsed := now.Sub(state.Timestamp).Seconds(); elapsed > 0 {
+		state.Tokens = math.Min(capacity, state.Tokens+elapsed*refillRate)
+	}
+	state.Timestamp = now
+
+	result := RateLimitResult{
+		Limit: rateLimit.Limit,
+	}
+
+	if state.Tokens >= 1 {
+		state.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - state.Tokens) / refillRate)
+	}
+
+	result.Remaining = int(math.Floor(state.Tokens))
+	result.Reset = secondsToDuration((capacity - state.Tokens) / refillRate)
+	return result
+}
+
+func (rateLimit RateLimit) takeSlidingWindow(state *RateLimitState, now time.Time) RateLimitResult {
+	windowStart := now.Truncate(rateLimit.Period)
+	if !state.Timestamp.Equal(windowStart) {
+		if state.Timestamp.Equal(windowStart.Add(-rateLimit.Period)) {
+			state.PreviousCount = state.Count
+		} else {
+			state.PreviousCount = 0
+		}
+		state.Count = 0
+		state.Timestamp = windowStart
+	}
+
+	elapsed := now.Sub(windowStart)
+	previousWeight := 1 - float64(elapsed)/float64(rateLimit.Period)
+	estimated := float64(state.PreviousCount)*previousWeight + float64(state.Count)
+
+	result := RateLimitResult{
+		Limit: rateLimit.Limit,
+		Reset: rateLimit.Period - elapsed,
+	}
+
+	if estimated+1 <= float64(rateLimit.Limit) {
+		state.Count++
+		estimated++
+		result.Allowed = true
+	} else if state.Count+1 > rateLimit.Limit || state.PreviousCount == 0 {
+		result.RetryAfter = rateLimit.Period - elapsed
+	} else {
+		allowedWeight := float64(rateLimit.Limit-state.Count-1) / float64(state.PreviousCount)
+		result.RetryAfter = time.Duration((1-allowedWeight)*float64(rateLimit.Period)) - elapsed
+	}
+
+	result.Remaining = rateLimit.Limit - int(math.Ceil(estimated))
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	return result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+type RateLimitKeyResolver func(ctx *WebRequestContext) string
+
+func RateLimitKeyByIP() RateLimitKeyResolver {
+	return func(ctx *WebRequestContext) string {
+		return ctx.ClientIP().String()
+	}
+}
+
+func RateLimitKeyByHeader(name string) RateLimitKeyResolver {
+	return func(ctx *WebRequestContext) string {
+		if value, ok := ctx.GetRequestHeader(name); ok && value != "" {
+			return name + ":" + value
+		}
+		return ctx.ClientIP().String()
+	}
+}
+
+func RateLimitKeyByPrincipal() RateLimitKeyResolver {
+	return func(ctx *WebRequestContext) string {
+		if principal := ctx.GetPrincipal(); principal != nil && principal.GetName() != "" {
+			return "principal:" + principal.GetName()
+		}
+		return ctx.ClientIP().String()
+	}
+}
+
+func newRateLimitKeyResolver(key string) RateLimitKeyResolver {
+	switch {
+	case key == "" || key == RateLimitKeyIP:
+		return RateLimitKeyByIP()
+	case key == RateLimitKeyPrincipal:
+		return RateLimitKeyByPrincipal()
+	case strings.HasPrefix(key, RateLimitKeyHeader) && len(key) > len(RateLimitKeyHeader):
+		return RateLimitKeyByHeader(key[len(RateLimitKeyHeader):])
+	default:
+		panic("Unsupported rate limit key : " + key)
+	}
+}
+
+type routeRateLimit struct {
+	pattern   string
+	rateLimit RateLimit
+}
+
+type RateLimitInterceptor struct {
+	enabled      bool
+	defaultLimit *RateLimit
+	routeLimits  []routeRateLimit
+	keyResolver  RateLimitKeyResolver
+	store        RateLimitStore
+	clock        func() time.Time
+}
+
+func NewRateLimitInterceptor(properties *RateLimitProperties) RateLimitInterceptor {
+	interceptor := RateLimitInterceptor{
+		enabled:     true,
+		routeLimits: make([]routeRateLimit, 0),
+		keyResolver: RateLimitKeyByIP(),
+		store:       NewInMemoryRateLimitStore(),
+		clock:       time.Now,
+	}
+
+	if properties == nil {
+		return interceptor
+	}
+
+	interceptor.enabled = properties.Enabled
+	interceptor.keyResolver = newRateLimitKeyResolver(properties.Key)
+
+	if properties.Limit > 0 {
+		algorithm := properties.Algorithm
+		if algorithm == "" {
+			algorithm = RateLimitAlgorithmTokenBucket
+		}
+
+		period := properties.Period
+		if period <= 0 {
+			period = 60
+		}
+
+		interceptor = interceptor.WithDefaultLimit(RateLimit{
+			Algorithm: algorithm,
+			Limit:     properties.Limit,
+			Period:    time.Duration(period) * time.Second,
+		})
+	}
+	return interceptor
+}
+
+func (interceptor RateLimitInterceptor) WithDefaultLimit(rateLimit RateLimit) RateLimitInterceptor {
+	rateLimit.validate()
+	interceptor.defaultLimit = &rateLimit
+	return interceptor
+}
+
+func (interceptor RateLimitInterceptor) WithRouteLimit(pattern string, rateLimit RateLimit) RateLimitInterceptor {
+	rateLimit.validate()
+
+	routeLimits := make([]routeRateLimit, len(interceptor.routeLimits), len(interceptor.routeLimits)+1)
+	copy(routeLimits, interceptor.routeLimits)
+	interceptor.routeLimits = append(routeLimits, routeRateLimit{pattern, rateLimit})
+	return interceptor
+}
+
+func (interceptor RateLimitInterceptor) WithKeyResolver(keyResolver RateLimitKeyResolver) RateLimitInterceptor {
+	if keyResolver == nil {
+		panic("Key resolver must not be null")
+	}
+
+	interceptor.keyResolver = keyResolver
+	return interceptor
+}
+
+func (interceptor RateLimitInterceptor) WithStore(store RateLimitStore) RateLimitInterceptor {
+	if store == nil {
+		panic("Store must not be null")
+	}
+
+	interceptor.store = store
+	return interceptor
+}
+
+func (interceptor RateLimitInterceptor) GetPriority() core.PriorityValue {
+	return RateLimitInterceptorPriority
+}
+
+func (interceptor RateLimitInterceptor) HandleBefore(ctx *WebRequestContext) {
+	if !interceptor.enabled {
+		return
+	}
+
+	rateLimit, keyPrefix := interceptor.findRateLimit(ctx.GetPath())
+	if rateLimit == nil {
+		return
+	}
+
+	key := interceptor.keyResolver(ctx)
+	if key == "" {
+		return
+	}
+
+	result := rateLimit.Take(interceptor.store, keyPrefix+key, interceptor.clock())
+	ctx.AddResponseHeader(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+	ctx.AddResponseHeader(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+	ctx.AddResponseHeader(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.Reset)))
+
+	if !result.Allowed {
+		ctx.AddResponseHeader(HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		ctx.SetHTTPError(HttpErrorTooManyRequests)
+		ctx.Cancel()
+	}
+}
+
+func (interceptor RateLimitInterceptor) findRateLimit(requestPath string) (*RateLimit, string) {
+	for index := range interceptor.routeLimits {
+		routeLimit := &interceptor.routeLimits[index]
+		if matchPathPattern(routeLimit.pattern, requestPath) {
+			return &routeLimit.rateLimit, "route:" + routeLimit.pattern + "|"
+		}
+	}
+	return interceptor.defaultLimit, ""
+}
+
+func ceilSeconds(duration time.Duration) int {
+	if duration <= 0 {
+		return 0
+	}
+	return int(math.Ceil(duration.Seconds()))
+}
@@ -0,0 +1,158 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type testPrincipal struct {
+	name string
+}
+
+func (principal testPrincipal) GetName() string {
+	return principal.name
+}
+
+func TestRateLimit_TokenBucket(t *testing.T) {
+	store := NewInMemoryRateLimitStore()
+	rateLimit := RateLimit{Algorithm: RateLimitAlgorithmTokenBucket, Limit: 2, Period: 10 * time.Second}
+	now := time.Unix(1600000000, 0)
+
+	result := rateLimit.Take(store, "client", now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+
+	result = rateLimit.Take(store, "client", now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, 10*time.Second, result.Reset)
+
+	result = rateLimit.Take(store, "client", now.Add(time.Second))
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 4*time.Second, result.RetryAfter)
+
+	result = rateLimit.Take(store, "client", now.Add(5*time.Second))
+	assert.True(t, result.Allowed)
+
+	result = rateLimit.Take(store, "other", now.Add(5*time.Second))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, store.Len())
+}
+
+func TestRateLimit_SlidingWindow(t *testing.T) {
+	store := NewInMemoryRateLimitStore()
+	rateLimit := RateLimit{Algorithm: RateLimitAlgorithmSlidingWindow, Limit: 4, Period: 10 * time.Second}
+	windowStart := time.Unix(1600000000, 0).Truncate(10 * time.Second)
+
+	for index := 0; index < 4; index++ {
+		assert.True(t, rateLimit.Take(store, "client", windowStart.Add(time.Second)).Allowed)
+	}
+
+	result := rateLimit.Take(store, "client", windowStart.Add(2*time.Second))
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, 8*time.Second, result.RetryAfter)
+
+	result = rateLimit.Take(store, "client", windowStart.Add(12*time.Second))
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	result = rateLimit.Take(store, "client", windowStart.Add(15*time.Second))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+
+	result = rateLimit.Take(store, "client", windowStart.Add(35*time.Second))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 3, result.Remaining)
+}
+
+func TestRateLimit_InvalidConfiguration(t *testing.T) {
+	store := NewInMemoryRateLimitStore()
+	assert.Panics(t, func() {
+		RateLimit{Limit: 0, Period: time.Second}.Take(store, "client", time.Now())
+	})
+	assert.Panics(t, func() {
+		RateLimit{Algorithm: "leaky-bucket", Limit: 1, Period: time.Second}.Take(store, "client", time.Now())
+	})
+	assert.Panics(t, func() {
+		NewRateLimitInterceptor(&RateLimitProperties{Enabled: true, Key: "cookie:session"})
+	})
+	assert.Panics(t, func() {
+		NewRateLimitInterceptor(&RateLimitProperties{Enabled: true, Limit: 1, Algorithm: "leaky-bucket"})
+	})
+	assert.Panics(t, func() {
+		NewRateLimitInterceptor(nil).WithDefaultLimit(RateLimit{Limit: 1})
+	})
+	assert.Panics(t, func() {
+		NewRateLimitInterceptor(nil).WithRouteLimit("/orders", RateLimit{Algorithm: "leaky-bucket", Limit: 1, Period: time.Second})
+	})
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	interceptor := NewRateLimitInterceptor(&RateLimitProperties{
+		Enabled:   true,
+		Algorithm: RateLimitAlgorithmTokenBucket,
+		Limit:     1,
+		Period:    60,
+	})
+	router := NewRouter(WithControllers(testNetHttpController{}), WithInterceptors(interceptor))
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.Equal(t, "1", string(response.Header.Peek(HeaderRateLimitLimit)))
+	assert.Equal(t, "0", string(response.Header.Peek(HeaderRateLimitRemaining)))
+	assert.Equal(t, "60", string(response.Header.Peek(HeaderRateLimitReset)))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", nil, "")
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode())
+	assert.Equal(t, "0", string(response.Header.Peek(HeaderRateLimitRemaining)))
+	assert.Equal(t, "60", string(response.Header.Peek(HeaderRetryAfter)))
+	assert.Empty(t, response.Header.Peek("X-Greeting"))
+}
+
+func TestRateLimitInterceptor_Disabled(t *testing.T) {
+	interceptor := NewRateLimitInterceptor(&RateLimitProperties{Limit: 1, Period: 60})
+	router := NewRouter(WithControllers(testNetHttpController{}), WithInterceptors(interceptor))
+
+	for index := 0; index < 3; index++ {
+		response := serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", nil, "")
+		assert.Equal(t, http.StatusOK, response.StatusCode())
+		assert.Empty(t, response.Header.Peek(HeaderRateLimitLimit))
+	}
+}
+
+func TestRateLimitInterceptor_RouteLimitAndHeaderKey(t *testing.T) {
+	interceptor := NewRateLimitInterceptor(&RateLimitProperties{Enabled: true, Key: "header:X-Api-Key"}).
+		WithRouteLimit("/greetings/*", RateLimit{Algorithm: RateLimitAlgorithmSlidingWindow, Limit: 1, Period: time.Minute})
+	router := NewRouter(WithControllers(testNetHttpController{}), WithInterceptors(interceptor))
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", map[string]string{"X-Api-Key": "first"}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", map[string]string{"X-Api-Key": "second"}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/greetings/web", map[string]string{"X-Api-Key": "first"}, "")
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode())
+
+	response = serveTestAdapterRequest(router, http.MethodPost, "/echo", map[string]string{"X-Api-Key": "first"}, "body")
+	assert.Equal(t, http.StatusCreated, response.StatusCode())
+	assert.Empty(t, response.Header.Peek(HeaderRateLimitLimit))
+}
+
+func TestRateLimitKeyResolvers(t *testing.T) {
+	ctx := NewWebRequestContext(RequestMethodGet, "/orders",
+		ContextRemoteAddr("203.0.113.7:8080"),
+		ContextHeader("X-Api-Key", "secret"),
+		ContextAuthentication(NewAuthentication(testPrincipal{"procyon"})),
+	)
+	assert.Equal(t, "203.0.113.7", RateLimitKeyByIP()(ctx))
+	assert.Equal(t, "X-Api-Key:secret", RateLimitKeyByHeader("X-Api-Key")(ctx))
+	assert.Equal(t, "203.0.113.7", RateLimitKeyByHeader("X-Client-Id")(ctx))
+	assert.Equal(t, "principal:procyon", RateLimitKeyByPrincipal()(ctx))
+
+	ctx.SetAuthentication(nil)
+	assert.Equal(t, "203.0.113.7", RateLimitKeyByPrincipal()(ctx))
+}
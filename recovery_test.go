@@ -1,6 +1,10 @@
 package web
 
 import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strconv"
 	"testing"
 )
 
@@ -32,3 +36,96 @@ func panicUnknown(requestContext *WebRequestContext) {
 	//defer recoveryFunction(requestContext)
 	//panic(1)
 }
+
+type testRecoveryController struct {
+}
+
+func (controller testRecoveryController) RegisterHandlers(registry HandlerRegistry) {
+	registry.Register(
+		Get(controller.panicWithError, Path("/error")),
+		Get(controller.panicWithHTTPError, Path("/forbidden")),
+		Get(controller.panicWithString, Path("/string")),
+		Get(controller.panicWithUnknownValue, Path("/unknown")),
+	)
+}
+
+func (controller testRecoveryController) panicWithError(ctx *WebRequestContext) {
+	panic(errors.New("error message"))
+}
+
+func (controller testRecoveryController) panicWithHTTPError(ctx *WebRequestContext) {
+	panic(HttpErrorForbidden)
+}
+
+func (controller testRecoveryController) panicWithString(ctx *WebRequestContext) {
+	panic("error message")
+}
+
+func (controller testRecoveryController) panicWithUnknownValue(ctx *WebRequestContext) {
+	panic(1)
+}
+
+type testRecoveryInterceptor struct {
+	calls *[]string
+}
+
+func (interceptor testRecoveryInterceptor) HandleBefore(ctx *WebRequestContext) {
+	*interceptor.calls = append(*interceptor.calls, "before")
+}
+
+func (interceptor testRecoveryInterceptor) HandleAfter(ctx *WebRequestContext) {
+	*interceptor.calls = append(*interceptor.calls, "after")
+}
+
+func (interceptor testRecoveryInterceptor) AfterCompletion(ctx *WebRequestContext) {
+	*interceptor.calls = append(*interceptor.calls, "afterCompletion:"+strconv.Itoa(ctx.fastHttpRequestContext.Response.StatusCode()))
+}
+
+func TestRecover_InvokesAfterCompletionInterceptors(t *testing.T) {
+	calls := make([]string, 0)
+	router := NewRouter(
+		WithControllers(testRecoveryController{}),
+		WithInterceptors(testRecoveryInterceptor{&calls}),
+	)
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/error", nil, "")
+	assert.Equal(t, http.StatusInternalServerError, response.StatusCode())
+	assert.Equal(t, []string{"before", "afterCompletion:500"}, calls)
+
+	calls = calls[:0]
+	response = serveTestAdapterRequest(router, http.MethodGet, "/forbidden", nil, "")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode())
+	assert.Equal(t, []string{"before", "afterCompletion:403"}, calls)
+}
+
+func TestRecover_InvokesAfterCompletionInterceptorsForAnyPanicValue(t *testing.T) {
+	calls := make([]string, 0)
+	router := NewRouter(
+		WithControllers(testRecoveryController{}),
+		WithInterceptors(testRecoveryInterceptor{&calls}),
+	)
+
+	for _, path := range []string{"/string", "/unknown"} {
+		calls = calls[:0]
+		response := serveTestAdapterRequest(router, http.MethodGet, path, nil, "")
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode())
+		assert.Equal(t, []string{"before", "afterCompletion:500"}, calls)
+	}
+}
+
+type testPanickingAfterCompletionInterceptor struct {
+}
+
+func (interceptor testPanickingAfterCompletionInterceptor) AfterCompletion(ctx *WebRequestContext) {
+	panic("after completion failure")
+}
+
+func TestRecover_KeepsResponseWhenAfterCompletionInterceptorPanics(t *testing.T) {
+	router := NewRouter(
+		WithControllers(testRecoveryController{}),
+		WithInterceptors(testPanickingAfterCompletionInterceptor{}),
+	)
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/forbidden", nil, "")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode())
+}
@@ -37,6 +37,9 @@ type RequestHandler struct {
 	HandlerFunc           RequestHandlerFunction
 	RequestObject         RequestHandlerObject
 	requestObjectMetadata *RequestObjectMetadata
+	webSocketUpgrader     *webSocketUpgrader
+	validateRequest       bool
+	securityRequirements  []SecurityRequirement
 }
 
 func newHandler(handler RequestHandlerFunction, method RequestMethod, options ...RequestHandlerOption) RequestHandler {
@@ -99,6 +102,12 @@ func RequestObject(requestObject RequestHandlerObject) RequestHandlerOption {
 	}
 }
 
+func Validate() RequestHandlerOption {
+	return func(handler *RequestHandler) {
+		handler.validateRequest = true
+	}
+}
+
 func Path(path string) RequestHandlerOption {
 	return func(handler *RequestHandler) {
 		handler.Path = path
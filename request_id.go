@@ -0,0 +1,52 @@
+package web
+
+const (
+	HeaderRequestId = "X-Request-ID"
+
+	minRequestIdLength = 8
+	maxRequestIdLength = 128
+)
+
+func isValidRequestId(value []byte) bool {
+	if len(value) < minRequestIdLength || len(value) > maxRequestIdLength {
+		return false
+	}
+
+	for _, character := range value {
+		switch {
+		case character >= 'a' && character <= 'z':
+		case character >= 'A' && character <= 'Z':
+		case character >= '0' && character <= '9':
+		case character == '-' || character == '_' || character == '.' || character == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (ctx *WebRequestContext) prepareRequestId() bool {
+	if ctx.router == nil || ctx.router.requestIdHeader == "" || ctx.fastHttpRequestContext == nil {
+		return false
+	}
+
+	requestId := ctx.fastHttpRequestContext.Request.Header.Peek(ctx.router.requestIdHeader)
+	if !isValidRequestId(requestId) {
+		return false
+	}
+
+	ctx.contextIdStr = string(requestId)
+	return true
+}
+
+func (ctx *WebRequestContext) echoRequestId() {
+	if ctx.router == nil || ctx.router.requestIdHeader == "" || !ctx.router.echoRequestId {
+		return
+	}
+
+	if ctx.fastHttpRequestContext == nil || ctx.contextIdStr == "" {
+		return
+	}
+
+	ctx.fastHttpRequestContext.Response.Header.Set(ctx.router.requestIdHeader, ctx.contextIdStr)
+}
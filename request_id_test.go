@@ -0,0 +1,58 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestIsValidRequestId(t *testing.T) {
+	assert.True(t, isValidRequestId([]byte("req-1234")))
+	assert.True(t, isValidRequestId([]byte("gateway:01F8MECHZX3TBDSZ7XRADM79XE")))
+	assert.False(t, isValidRequestId([]byte("short")))
+	assert.False(t, isValidRequestId([]byte("req 1234 with spaces")))
+	assert.False(t, isValidRequestId([]byte("req-1234\r\nX-Injected: true")))
+	assert.False(t, isValidRequestId([]byte(strings.Repeat("a", maxRequestIdLength+1))))
+}
+
+func TestWebRequestContext_RequestId(t *testing.T) {
+	ctx := NewWebRequestContext(RequestMethodGet, "/orders",
+		ContextHeader(HeaderRequestId, "gateway-request-1"),
+		ContextHeader(HeaderTraceparent, testTraceparent),
+	)
+	assert.Equal(t, "gateway-request-1", string(ctx.GetContextId()))
+
+	response := &ctx.fastHttpRequestContext.Response
+	assert.Equal(t, "gateway-request-1", string(response.Header.Peek(HeaderRequestId)))
+
+	ctx = NewWebRequestContext(RequestMethodGet, "/orders", ContextHeader(HeaderRequestId, "bad id"))
+	assert.Len(t, string(ctx.GetContextId()), 36)
+
+	response = &ctx.fastHttpRequestContext.Response
+	assert.Equal(t, string(ctx.GetContextId()), string(response.Header.Peek(HeaderRequestId)))
+}
+
+func TestRouter_RequestIdProperties(t *testing.T) {
+	router := NewRouter(WithControllers(testNetHttpController{}))
+	response := serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", map[string]string{HeaderRequestId: "gateway-request-1"}, "")
+	assert.Equal(t, "gateway-request-1", string(response.Header.Peek(HeaderRequestId)))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/unknown", nil, "")
+	assert.Len(t, string(response.Header.Peek(HeaderRequestId)), 36)
+
+	router = NewRouter(
+		WithControllers(testNetHttpController{}),
+		WithRequestIdProperties(&RequestIdProperties{Enabled: true, Header: "X-Correlation-ID", Echo: false}),
+	)
+	response = serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", map[string]string{"X-Correlation-ID": "correlation-1"}, "")
+	assert.Empty(t, response.Header.Peek("X-Correlation-ID"))
+	assert.Empty(t, response.Header.Peek(HeaderRequestId))
+
+	router = NewRouter(
+		WithControllers(testNetHttpController{}),
+		WithRequestIdProperties(&RequestIdProperties{Enabled: false, Echo: true}),
+	)
+	response = serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", map[string]string{HeaderRequestId: "gateway-request-1"}, "")
+	assert.Empty(t, response.Header.Peek(HeaderRequestId))
+}
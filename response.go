@@ -8,17 +8,19 @@ import (
 type MediaType byte
 
 const (
-	DefaultMediaType                       = MediaTypeApplicationTextHtml
-	MediaTypeApplicationTextHtml MediaType = 0
-	MediaTypeApplicationJson     MediaType = 1
-	MediaTypeApplicationXml      MediaType = 2
+	DefaultMediaType                          = MediaTypeApplicationTextHtml
+	MediaTypeApplicationTextHtml    MediaType = 0
+	MediaTypeApplicationJson        MediaType = 1
+	MediaTypeApplicationXml         MediaType = 2
+	MediaTypeApplicationProblemJson MediaType = 3
 )
 
 const (
-	DefaultMediaTypeValue             = MediaTypeApplicationTextHtmlValue
-	MediaTypeApplicationTextHtmlValue = "text/html"
-	MediaTypeApplicationXmlValue      = "application/xml"
-	MediaTypeApplicationJsonValue     = "application/json"
+	DefaultMediaTypeValue                = MediaTypeApplicationTextHtmlValue
+	MediaTypeApplicationTextHtmlValue    = "text/html"
+	MediaTypeApplicationXmlValue         = "application/xml"
+	MediaTypeApplicationJsonValue        = "application/json"
+	MediaTypeApplicationProblemJsonValue = "application/problem+json"
 )
 
 type ResponseHeaderBuilder interface {
@@ -70,7 +72,7 @@ func newDefaultResponseBodyWriter() defaultResponseBodyWriter {
 }
 
 func (bodyWriter defaultResponseBodyWriter) WriteResponseBody(ctx *WebRequestContext, responseWriter ResponseWriter) error {
-	if ctx.responseEntity.contentType == MediaTypeApplicationJson {
+	if ctx.responseEntity.contentType == MediaTypeApplicationJson || ctx.responseEntity.contentType == MediaTypeApplicationProblemJson {
 		if ctx.responseEntity.model == nil {
 			return nil
 		}
@@ -4,6 +4,8 @@ import (
 	"github.com/procyon-projects/goo"
 	context "github.com/procyon-projects/procyon-context"
 	"github.com/valyala/fasthttp"
+	"net"
+	"strings"
 	"sync"
 )
 
@@ -12,20 +14,33 @@ type Router interface {
 }
 
 type ProcyonRouter struct {
-	ctx                 context.ConfigurableApplicationContext
-	handlerMapping      HandlerMapping
-	requestContextPool  *sync.Pool
-	generateContextId   bool
-	recoveryActive      bool
-	errorHandlerManager *errorHandlerManager
-	validator           Validator
-	requestBinder       RequestBinder
-	responseBodyWriter  ResponseBodyWriter
+	ctx                   context.ConfigurableApplicationContext
+	logger                context.Logger
+	handlerMapping        HandlerMapping
+	notFoundChain         *HandlerChain
+	requestContextPool    *sync.Pool
+	generateContextId     bool
+	recoveryActive        bool
+	errorHandlerManager   *errorHandlerManager
+	validator             Validator
+	localeResolver        LocaleResolver
+	messageSource         MessageSource
+	requestBinder         RequestBinder
+	responseBodyWriter    ResponseBodyWriter
+	spanExporter          SpanExporter
+	requestIdHeader       string
+	echoRequestId         bool
+	trustedProxies        []*net.IPNet
+	forwardedHeader       string
+	authenticatorRegistry AuthenticatorRegistry
+	securityRuleRegistry  SecurityRuleRegistry
+	ipFilterRuleSet       *IPFilterRuleSet
 }
 
 func newProcyonRouterForBenchmark(context context.ConfigurableApplicationContext, handlerRegistry SimpleHandlerRegistry) *ProcyonRouter {
 	router := &ProcyonRouter{
-		ctx: context,
+		ctx:    context,
+		logger: context.GetLogger(),
 	}
 	router.requestContextPool = &sync.Pool{
 		New: router.newWebRequestContext,
@@ -44,11 +59,14 @@ func newProcyonRouterForBenchmark(context context.ConfigurableApplicationContext
 func NewProcyonRouter(context context.ConfigurableApplicationContext) *ProcyonRouter {
 	router := &ProcyonRouter{
 		ctx:                context,
+		logger:             context.GetLogger(),
 		generateContextId:  true,
 		recoveryActive:     true,
-		validator:          newDefaultValidator(),
+		localeResolver:     NewDefaultLocaleResolver(DefaultLocale),
 		requestBinder:      newDefaultRequestBinder(),
 		responseBodyWriter: newDefaultResponseBodyWriter(),
+		requestIdHeader:    HeaderRequestId,
+		echoRequestId:      true,
 	}
 	router.requestContextPool = &sync.Pool{
 		New: router.newWebRequestContext,
@@ -57,6 +75,40 @@ func NewProcyonRouter(context context.ConfigurableApplicationContext) *ProcyonRo
 	return router
 }
 
+func (router *ProcyonRouter) configureRequestId(properties *RequestIdProperties) {
+	router.requestIdHeader = ""
+	if properties.Enabled {
+		router.requestIdHeader = properties.Header
+		if router.requestIdHeader == "" {
+			router.requestIdHeader = HeaderRequestId
+		}
+	}
+	router.echoRequestId = properties.Echo
+}
+
+func (router *ProcyonRouter) configureTrustedProxies(values ...string) {
+	trustedProxies, err := ParseTrustedProxies(values...)
+	if err != nil {
+		panic("Invalid trusted proxy : " + err.Error())
+	}
+	router.trustedProxies = trustedProxies
+}
+
+func (router *ProcyonRouter) configureNotFoundChain() {
+	var interceptorRegistry HandlerInterceptorRegistry
+	if handlerMapping, ok := router.handlerMapping.(RequestHandlerMapping); ok {
+		if simpleRegistry, ok := handlerMapping.interceptorRegistry.(*SimpleHandlerInterceptorRegistry); ok {
+			interceptorRegistry = simpleRegistry.getUnmatchedRequestInterceptorRegistry()
+		}
+	}
+	router.notFoundChain = NewHandlerChain(router.handleNotFound, interceptorRegistry, nil)
+}
+
+func (router *ProcyonRouter) handleNotFound(ctx *WebRequestContext) {
+	router.logger.Warning(ctx, "Handler not found : "+string(ctx.fastHttpRequestContext.Path()))
+	ctx.SetHTTPError(HttpErrorNotFound)
+}
+
 func (router *ProcyonRouter) newWebRequestContext() interface{} {
 	requestContext := &WebRequestContext{
 		router:       router,
@@ -71,18 +123,56 @@ func (router *ProcyonRouter) configure() {
 
 	handlerAdapter := peaFactory.GetSharedPeaType(goo.GetType((*HandlerMapping)(nil)))
 	router.handlerMapping = handlerAdapter.(HandlerMapping)
+	router.configureNotFoundChain()
 
 	// custom logger
-	router.errorHandlerManager = newErrorHandlerManager(router.ctx.GetLogger())
+	router.errorHandlerManager = newErrorHandlerManager(router.logger)
+	errorProperties, _ := peaFactory.GetPeaByType(goo.GetType((*ErrorProperties)(nil)))
+	if errorProperties != nil {
+		defaultErrorHandler := NewDefaultErrorHandler(router.logger)
+		if errorProperties.(*ErrorProperties).ProblemDetails {
+			defaultErrorHandler = NewProblemDetailsErrorHandler(router.logger)
+		}
+		router.errorHandlerManager.defaultErrorHandler = defaultErrorHandler.WithValidationErrorStatus(errorProperties.(*ErrorProperties).ValidationErrorStatus)
+	}
 	errorHandler, _ := peaFactory.GetPeaByType(goo.GetType((*ErrorHandler)(nil)))
 	if errorHandler != nil {
 		router.errorHandlerManager.customErrorHandler = errorHandler.(ErrorHandler)
 	}
 
+	// error handler registry
+	errorHandlerRegistry, _ := peaFactory.GetPeaByType(goo.GetType((*ErrorHandlerRegistry)(nil)))
+	if errorHandlerRegistry != nil {
+		router.errorHandlerManager.errorHandlerRegistry = errorHandlerRegistry.(ErrorHandlerRegistry)
+	}
+
+	// locale resolver and message source
+	localeProperties, _ := peaFactory.GetPeaByType(goo.GetType((*LocaleProperties)(nil)))
+	if localeProperties != nil {
+		properties := localeProperties.(*LocaleProperties)
+		router.localeResolver = NewDefaultLocaleResolver(properties.Default, strings.Split(properties.Supported, ",")...).
+			WithParameterName(properties.ParameterName).
+			WithCookieName(properties.CookieName)
+	}
+
+	customLocaleResolver, _ := peaFactory.GetPeaByType(goo.GetType((*LocaleResolver)(nil)))
+	if customLocaleResolver != nil {
+		router.localeResolver = customLocaleResolver.(LocaleResolver)
+	}
+
+	customMessageSource, _ := peaFactory.GetPeaByType(goo.GetType((*MessageSource)(nil)))
+	if customMessageSource != nil {
+		router.messageSource = customMessageSource.(MessageSource)
+	} else {
+		router.messageSource = NewTranslatorMessageSource()
+	}
+
 	// custom validator
 	customValidator, _ := peaFactory.GetPeaByType(goo.GetType((*Validator)(nil)))
 	if customValidator != nil {
 		router.validator = customValidator.(Validator)
+	} else {
+		router.validator = newDefaultValidator(router.messageSource)
 	}
 
 	// custom request binder
@@ -96,6 +186,50 @@ func (router *ProcyonRouter) configure() {
 	if customResponseBodyWriter != nil {
 		router.responseBodyWriter = customResponseBodyWriter.(ResponseBodyWriter)
 	}
+
+	// request id
+	requestIdProperties, _ := peaFactory.GetPeaByType(goo.GetType((*RequestIdProperties)(nil)))
+	if requestIdProperties != nil {
+		router.configureRequestId(requestIdProperties.(*RequestIdProperties))
+	}
+
+	// trusted proxies
+	forwardedProperties, _ := peaFactory.GetPeaByType(goo.GetType((*ForwardedProperties)(nil)))
+	if forwardedProperties != nil {
+		router.configureTrustedProxies(strings.Split(forwardedProperties.(*ForwardedProperties).TrustedProxies, ",")...)
+		router.forwardedHeader = parseForwardedHeader(forwardedProperties.(*ForwardedProperties).Header)
+	}
+
+	// authenticators and security rules
+	authenticatorRegistry, _ := peaFactory.GetPeaByType(goo.GetType((*AuthenticatorRegistry)(nil)))
+	if authenticatorRegistry != nil {
+		router.authenticatorRegistry = authenticatorRegistry.(AuthenticatorRegistry)
+	}
+
+	securityRuleRegistry, _ := peaFactory.GetPeaByType(goo.GetType((*SecurityRuleRegistry)(nil)))
+	if securityRuleRegistry != nil {
+		router.securityRuleRegistry = securityRuleRegistry.(SecurityRuleRegistry)
+	}
+
+	// ip filter
+	ipFilterProperties, _ := peaFactory.GetPeaByType(goo.GetType((*IPFilterProperties)(nil)))
+	if ipFilterProperties != nil && ipFilterProperties.(*IPFilterProperties).Enabled {
+		ipFilterRuleSet, _ := peaFactory.GetPeaByType(goo.GetType((*IPFilterRuleSet)(nil)))
+		if ipFilterRuleSet != nil {
+			router.ipFilterRuleSet = ipFilterRuleSet.(*IPFilterRuleSet)
+		}
+	}
+
+	// span exporter
+	tracingProperties, _ := peaFactory.GetPeaByType(goo.GetType((*TracingProperties)(nil)))
+	if tracingProperties != nil {
+		router.spanExporter = newSpanExporter(tracingProperties.(*TracingProperties).Exporter)
+	}
+
+	customSpanExporter, _ := peaFactory.GetPeaByType(goo.GetType((*SpanExporter)(nil)))
+	if customSpanExporter != nil {
+		router.spanExporter = customSpanExporter.(SpanExporter)
+	}
 }
 
 func (router *ProcyonRouter) Route(requestCtx *fasthttp.RequestCtx) {
@@ -104,13 +238,27 @@ func (router *ProcyonRouter) Route(requestCtx *fasthttp.RequestCtx) {
 	// prepare the context
 	requestContext.prepare(router.generateContextId)
 
+	if !router.isAllowedByIPFilter(requestContext) {
+		router.errorHandlerManager.HandleError(HttpErrorForbidden, requestContext)
+
+		requestContext.finishTrace()
+		requestContext.reset()
+		router.requestContextPool.Put(requestContext)
+		return
+	}
+
 	// get handler chain and call all handlers
 	router.handlerMapping.GetHandlerChain(requestContext)
 
+	if requestContext.handlerChain == nil && router.notFoundChain != nil {
+		requestContext.handlerChain = router.notFoundChain
+	}
+
 	if requestContext.handlerChain == nil {
-		router.ctx.GetLogger().Warning(requestContext, "Handler not found : "+string(requestCtx.Path()))
+		router.logger.Warning(requestContext, "Handler not found : "+string(requestCtx.Path()))
 		router.errorHandlerManager.HandleError(HttpErrorNotFound, requestContext)
 
+		requestContext.finishTrace()
 		requestContext.reset()
 		router.requestContextPool.Put(requestContext)
 		return
@@ -118,6 +266,7 @@ func (router *ProcyonRouter) Route(requestCtx *fasthttp.RequestCtx) {
 
 	requestContext.invoke()
 
+	requestContext.finishTrace()
 	requestContext.reset()
 	router.requestContextPool.Put(requestContext)
 }
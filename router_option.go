@@ -0,0 +1,245 @@
+package web
+
+import (
+	context "github.com/procyon-projects/procyon-context"
+	"sync"
+)
+
+type RouterOption func(options *routerOptions)
+
+type routerOptions struct {
+	logger              context.Logger
+	controllers         []Controller
+	interceptors        []interface{}
+	errorAdvices        []ErrorAdvice
+	errorHandler        ErrorHandler
+	validator           Validator
+	requestBinder       RequestBinder
+	responseBodyWriter  ResponseBodyWriter
+	localeResolver      LocaleResolver
+	messageSource       MessageSource
+	errorProperties     *ErrorProperties
+	spanExporter        SpanExporter
+	requestIdProperties *RequestIdProperties
+	trustedProxies      []string
+	forwardedHeader     string
+	authenticators      []Authenticator
+	securityRules       []SecurityRule
+	ipFilterRuleSet     *IPFilterRuleSet
+}
+
+func WithLogger(logger context.Logger) RouterOption {
+	return func(options *routerOptions) {
+		options.logger = logger
+	}
+}
+
+func WithControllers(controllers ...Controller) RouterOption {
+	return func(options *routerOptions) {
+		options.controllers = append(options.controllers, controllers...)
+	}
+}
+
+func WithManagementControllers(controllers ...ManagementController) RouterOption {
+	return func(options *routerOptions) {
+		for _, controller := range controllers {
+			options.controllers = append(options.controllers, managementControllerAdapter{controller})
+		}
+	}
+}
+
+func WithInterceptors(interceptors ...interface{}) RouterOption {
+	return func(options *routerOptions) {
+		options.interceptors = append(options.interceptors, interceptors...)
+	}
+}
+
+func WithErrorAdvices(errorAdvices ...ErrorAdvice) RouterOption {
+	return func(options *routerOptions) {
+		options.errorAdvices = append(options.errorAdvices, errorAdvices...)
+	}
+}
+
+func WithErrorHandler(errorHandler ErrorHandler) RouterOption {
+	return func(options *routerOptions) {
+		options.errorHandler = errorHandler
+	}
+}
+
+func WithErrorProperties(errorProperties *ErrorProperties) RouterOption {
+	return func(options *routerOptions) {
+		options.errorProperties = errorProperties
+	}
+}
+
+func WithValidator(validator Validator) RouterOption {
+	return func(options *routerOptions) {
+		options.validator = validator
+	}
+}
+
+func WithRequestBinder(requestBinder RequestBinder) RouterOption {
+	return func(options *routerOptions) {
+		options.requestBinder = requestBinder
+	}
+}
+
+func WithResponseBodyWriter(responseBodyWriter ResponseBodyWriter) RouterOption {
+	return func(options *routerOptions) {
+		options.responseBodyWriter = responseBodyWriter
+	}
+}
+
+func WithLocaleResolver(localeResolver LocaleResolver) RouterOption {
+	return func(options *routerOptions) {
+		options.localeResolver = localeResolver
+	}
+}
+
+func WithMessageSource(messageSource MessageSource) RouterOption {
+	return func(options *routerOptions) {
+		options.messageSource = messageSource
+	}
+}
+
+func WithSpanExporter(spanExporter SpanExporter) RouterOption {
+	return func(options *routerOptions) {
+		options.spanExporter = spanExporter
+	}
+}
+
+func WithRequestIdProperties(requestIdProperties *RequestIdProperties) RouterOption {
+	return func(options *routerOptions) {
+		options.requestIdProperties = requestIdProperties
+	}
+}
+
+func WithTrustedProxies(trustedProxies ...string) RouterOption {
+	return func(options *routerOptions) {
+		options.trustedProxies = append(options.trustedProxies, trustedProxies...)
+	}
+}
+
+func WithForwardedHeader(forwardedHeader string) RouterOption {
+	return func(options *routerOptions) {
+		options.forwardedHeader = forwardedHeader
+	}
+}
+
+func WithAuthenticators(authenticators ...Authenticator) RouterOption {
+	return func(options *routerOptions) {
+		options.authenticators = append(options.authenticators, authenticators...)
+	}
+}
+
+func WithSecurityRules(securityRules ...SecurityRule) RouterOption {
+	return func(options *routerOptions) {
+		options.securityRules = append(options.securityRules, securityRules...)
+	}
+}
+
+func WithIPFilter(ruleSet *IPFilterRuleSet) RouterOption {
+	return func(options *routerOptions) {
+		options.ipFilterRuleSet = ruleSet
+	}
+}
+
+func NewRouter(options ...RouterOption) *ProcyonRouter {
+	routerOptions := &routerOptions{
+		logger: context.NewSimpleLogger(),
+	}
+
+	for _, option := range options {
+		option(routerOptions)
+	}
+
+	router := &ProcyonRouter{
+		logger:             routerOptions.logger,
+		generateContextId:  true,
+		recoveryActive:     true,
+		localeResolver:     NewDefaultLocaleResolver(DefaultLocale),
+		requestBinder:      newDefaultRequestBinder(),
+		responseBodyWriter: newDefaultResponseBodyWriter(),
+		requestIdHeader:    HeaderRequestId,
+		echoRequestId:      true,
+	}
+	router.requestContextPool = &sync.Pool{
+		New: router.newWebRequestContext,
+	}
+
+	interceptorRegistry := NewSimpleHandlerInterceptorRegistry()
+	if len(routerOptions.authenticators) != 0 {
+		authenticatorRegistry := NewSimpleAuthenticatorRegistry()
+		authenticatorRegistry.RegisterAuthenticator(routerOptions.authenticators...)
+		interceptorRegistry.RegisterHandlerInterceptor(NewAuthenticationInterceptor(authenticatorRegistry, nil))
+		router.authenticatorRegistry = authenticatorRegistry
+	}
+
+	if len(routerOptions.securityRules) != 0 {
+		securityRuleRegistry := NewSimpleSecurityRuleRegistry()
+		securityRuleRegistry.RegisterSecurityRule(routerOptions.securityRules...)
+		router.securityRuleRegistry = securityRuleRegistry
+	}
+
+	for _, interceptor := range routerOptions.interceptors {
+		interceptorRegistry.RegisterHandlerInterceptor(interceptor)
+	}
+
+	handlerMapping := NewRequestHandlerMapping(NewRequestMappingRegistry(), interceptorRegistry)
+	mappingProcessor := NewRequestHandlerMappingProcessor(handlerMapping)
+	for _, controller := range routerOptions.controllers {
+		_, _ = mappingProcessor.BeforePeaInitialization("", controller)
+	}
+	router.handlerMapping = handlerMapping
+	router.configureNotFoundChain()
+
+	errorHandlerRegistry := NewSimpleErrorHandlerRegistry()
+	for _, errorAdvice := range routerOptions.errorAdvices {
+		errorAdvice.RegisterErrorHandlers(errorHandlerRegistry)
+	}
+
+	router.errorHandlerManager = newErrorHandlerManager(router.logger)
+	router.errorHandlerManager.errorHandlerRegistry = errorHandlerRegistry
+	router.errorHandlerManager.customErrorHandler = routerOptions.errorHandler
+	if routerOptions.errorProperties != nil {
+		defaultErrorHandler := NewDefaultErrorHandler(router.logger)
+		if routerOptions.errorProperties.ProblemDetails {
+			defaultErrorHandler = NewProblemDetailsErrorHandler(router.logger)
+		}
+		router.errorHandlerManager.defaultErrorHandler = defaultErrorHandler.WithValidationErrorStatus(routerOptions.errorProperties.ValidationErrorStatus)
+	}
+
+	if routerOptions.requestBinder != nil {
+		router.requestBinder = routerOptions.requestBinder
+	}
+
+	if routerOptions.responseBodyWriter != nil {
+		router.responseBodyWriter = routerOptions.responseBodyWriter
+	}
+
+	if routerOptions.localeResolver != nil {
+		router.localeResolver = routerOptions.localeResolver
+	}
+
+	router.messageSource = routerOptions.messageSource
+	if router.messageSource == nil {
+		router.messageSource = NewTranslatorMessageSource()
+	}
+
+	router.validator = routerOptions.validator
+	if router.validator == nil {
+		router.validator = newDefaultValidator(router.messageSource)
+	}
+
+	router.spanExporter = routerOptions.spanExporter
+	router.ipFilterRuleSet = routerOptions.ipFilterRuleSet
+
+	if routerOptions.requestIdProperties != nil {
+		router.configureRequestId(routerOptions.requestIdProperties)
+	}
+
+	router.configureTrustedProxies(routerOptions.trustedProxies...)
+	router.forwardedHeader = parseForwardedHeader(routerOptions.forwardedHeader)
+
+	return router
+}
@@ -6,7 +6,8 @@ import (
 )
 
 type RouterTree struct {
-	methodTrees []*RouterMethodTree
+	methodTrees   []*RouterMethodTree
+	handlerChains []*HandlerChain
 }
 
 func newRouterTree() *RouterTree {
@@ -47,6 +48,7 @@ func (tree *RouterTree) AddRoute(path string, method RequestMethod, handlerChain
 	}
 	methodNode.add([]byte(path), handlerChain)
 	methodNode.registeredRoutes = append(methodNode.registeredRoutes, path)
+	tree.handlerChains = append(tree.handlerChains, handlerChain)
 }
 
 func (tree *RouterTree) Get(ctx *WebRequestContext) {
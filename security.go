@@ -0,0 +1,338 @@
+package web
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	RolePrefix  = "ROLE_"
+	ScopePrefix = "SCOPE_"
+)
+
+func (authentication *Authentication) HasRole(role string) bool {
+	return authentication.HasAuthority(RolePrefix + role)
+}
+
+func (authentication *Authentication) HasScope(scope string) bool {
+	return authentication.HasAuthority(scope) || authentication.HasAuthority(ScopePrefix+scope)
+}
+
+type SecurityRequirement interface {
+	IsSatisfiedBy(authentication *Authentication) bool
+	String() string
+}
+
+type securityRequirement struct {
+	description string
+	predicate   func(authentication *Authentication) bool
+}
+
+func (requirement securityRequirement) IsSatisfiedBy(authentication *Authentication) bool {
+	if requirement.predicate == nil {
+		return true
+	}
+
+	if authentication == nil || authentication.Principal == nil {
+		return false
+	}
+	return requirement.predicate(authentication)
+}
+
+func (requirement securityRequirement) String() string {
+	return requirement.description
+}
+
+func PermitAll() SecurityRequirement {
+	return securityRequirement{
+		description: "permitAll",
+	}
+}
+
+func Authenticated() SecurityRequirement {
+	return securityRequirement{
+		description: "authenticated",
+		predicate: func(authentication *Authentication) bool {
+			return true
+		},
+	}
+}
+
+func RoleAny(roles ...string) SecurityRequirement {
+	return securityRequirement{
+		description: "roleAny(" + strings.Join(roles, ",") + ")",
+		predicate: func(authentication *Authentication) bool {
+			for _, role := range roles {
+				if authentication.HasRole(role) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+func RoleAll(roles ...string) SecurityRequirement {
+	return securityRequirement{
+		description: "roleAll(" + strings.Join(roles, ",") + ")",
+		predicate: func(authentication *Authentication) bool {
+			for _, role := range roles {
+				if !authentication.HasRole(role) {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+func Scope(scopes ...string) SecurityRequirement {
+	return securityRequirement{
+		description: "scope(" + strings.Join(scopes, ",") + ")",
+		predicate: func(authentication *Authentication) bool {
+			for _, scope := range scopes {
+				if !authentication.HasScope(scope) {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+func Secured(requirements ...SecurityRequirement) RequestHandlerOption {
+	return func(handler *RequestHandler) {
+		handler.securityRequirements = append(handler.securityRequirements, requirements...)
+	}
+}
+
+type SecurityRule struct {
+	Pattern      string
+	Methods      []RequestMethod
+	Requirements []SecurityRequirement
+}
+
+func NewSecurityRule(pattern string, requirements ...SecurityRequirement) SecurityRule {
+	return SecurityRule{
+		Pattern:      pattern,
+		Requirements: requirements,
+	}
+}
+
+func (rule SecurityRule) WithMethods(methods ...RequestMethod) SecurityRule {
+	rule.Methods = methods
+	return rule
+}
+
+func (rule SecurityRule) matches(method RequestMethod, pattern string) bool {
+	if len(rule.Methods) != 0 {
+		matched := false
+		for _, ruleMethod := range rule.Methods {
+			if ruleMethod == method {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+	return matchPathPattern(rule.Pattern, pattern)
+}
+
+type SecurityConfigurer interface {
+	ConfigureSecurity(registry SecurityRuleRegistry)
+}
+
+type SecurityRuleRegistry interface {
+	RegisterSecurityRule(rules ...SecurityRule)
+	FindSecurityRule(method RequestMethod, pattern string) (SecurityRule, bool)
+}
+
+type SimpleSecurityRuleRegistry struct {
+	rules   []SecurityRule
+	version uint64
+	mu      sync.RWMutex
+}
+
+func NewSimpleSecurityRuleRegistry() *SimpleSecurityRuleRegistry {
+	return &SimpleSecurityRuleRegistry{
+		rules: make([]SecurityRule, 0),
+	}
+}
+
+func (registry *SimpleSecurityRuleRegistry) RegisterSecurityRule(rules ...SecurityRule) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for _, rule := range rules {
+		if rule.Pattern == "" {
+			panic("Security rule pattern must not be empty")
+		}
+		registry.rules = append(registry.rules, rule)
+	}
+	atomic.AddUint64(&registry.version, 1)
+}
+
+func (registry *SimpleSecurityRuleRegistry) getVersion() uint64 {
+	return atomic.LoadUint64(&registry.version)
+}
+
+func (registry *SimpleSecurityRuleRegistry) FindSecurityRule(method RequestMethod, pattern string) (SecurityRule, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	for _, rule := range registry.rules {
+		if rule.matches(method, pattern) {
+			return rule, true
+		}
+	}
+	return SecurityRule{}, false
+}
+
+func getSecurityRequirements(chain *HandlerChain, registry SecurityRuleRegistry, requestPath string) []SecurityRequirement {
+	if registry == nil {
+		return chain.securityRequirements
+	}
+
+	rule, ok := registry.FindSecurityRule(chain.method, requestPath)
+	if !ok && requestPath != chain.pattern {
+		rule, ok = registry.FindSecurityRule(chain.method, chain.pattern)
+	}
+
+	if !ok {
+		return chain.securityRequirements
+	}
+
+	requirements := make([]SecurityRequirement, 0, len(rule.Requirements)+len(chain.securityRequirements))
+	requirements = append(requirements, rule.Requirements...)
+	return append(requirements, chain.securityRequirements...)
+}
+
+type securityRequirementCache struct {
+	version      uint64
+	requirements []SecurityRequirement
+}
+
+func (chain *HandlerChain) hasDynamicPattern() bool {
+	return strings.ContainsAny(chain.pattern, ":*")
+}
+
+func (chain *HandlerChain) getSecurityRequirements(registry SecurityRuleRegistry, requestPath string) []SecurityRequirement {
+	if chain.hasDynamicPattern() {
+		return getSecurityRequirements(chain, registry, requestPath)
+	}
+
+	simpleRegistry, ok := registry.(*SimpleSecurityRuleRegistry)
+	if !ok {
+		return getSecurityRequirements(chain, registry, chain.pattern)
+	}
+
+	version := simpleRegistry.getVersion()
+	if cache, ok := chain.securityRequirementCache.Load().(*securityRequirementCache); ok && cache.version == version {
+		return cache.requirements
+	}
+
+	requirements := getSecurityRequirements(chain, registry, chain.pattern)
+	chain.securityRequirementCache.Store(&securityRequirementCache{
+		version:      version,
+		requirements: requirements,
+	})
+	return requirements
+}
+
+func (ctx *WebRequestContext) authorize() bool {
+	if ctx.router == nil || ctx.handlerChain == ctx.router.notFoundChain {
+		return true
+	}
+
+	requirements := ctx.handlerChain.getSecurityRequirements(ctx.router.securityRuleRegistry, ctx.GetPath())
+	for _, requirement := range requirements {
+		if requirement.IsSatisfiedBy(ctx.authentication) {
+			continue
+		}
+
+		if ctx.IsAuthenticated() {
+			ctx.SetHTTPError(HttpErrorForbidden)
+			return false
+		}
+
+		var authenticators []Authenticator
+		if ctx.router.authenticatorRegistry != nil {
+			authenticators = ctx.router.authenticatorRegistry.GetAuthenticators()
+		}
+		challengeAuthentication(ctx, authenticators, -1, nil)
+		return false
+	}
+	return true
+}
+
+type RouteInfo struct {
+	Method   RequestMethod `json:"method"`
+	Pattern  string        `json:"pattern"`
+	Security []string      `json:"security"`
+}
+
+func describeRoutes(handlerMapping HandlerMapping, registry SecurityRuleRegistry) []RouteInfo {
+	routes := make([]RouteInfo, 0)
+
+	requestHandlerMapping, ok := handlerMapping.(RequestHandlerMapping)
+	if !ok {
+		return routes
+	}
+
+	mappingRegistry, ok := requestHandlerMapping.mappingRegistry.(RequestMappingRegistry)
+	if !ok {
+		return routes
+	}
+
+	for _, chain := range mappingRegistry.routerTree.handlerChains {
+		security := make([]string, 0)
+		for _, requirement := range getSecurityRequirements(chain, registry, chain.pattern) {
+			security = append(security, requirement.String())
+		}
+
+		routes = append(routes, RouteInfo{
+			Method:   chain.method,
+			Pattern:  chain.pattern,
+			Security: security,
+		})
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Pattern != routes[j].Pattern {
+			return routes[i].Pattern < routes[j].Pattern
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+func (router *ProcyonRouter) GetRoutes() []RouteInfo {
+	return describeRoutes(router.handlerMapping, router.securityRuleRegistry)
+}
+
+type RoutesController struct {
+	handlerMapping HandlerMapping
+	registry       SecurityRuleRegistry
+}
+
+func NewRoutesController(handlerMapping RequestHandlerMapping, registry SecurityRuleRegistry) RoutesController {
+	return RoutesController{
+		handlerMapping,
+		registry,
+	}
+}
+
+func (controller RoutesController) RegisterManagementHandlers(registry HandlerRegistry) {
+	registry.Register(Get(controller.getRoutes, Path("/routes")))
+}
+
+func (controller RoutesController) getRoutes(ctx *WebRequestContext) {
+	ctx.Ok().
+		SetModel(describeRoutes(controller.handlerMapping, controller.registry)).
+		SetResponseContentType(MediaTypeApplicationJson)
+}
@@ -0,0 +1,229 @@
+package web
+
+import (
+	core "github.com/procyon-projects/procyon-core"
+	"strconv"
+	"strings"
+)
+
+const (
+	HeaderStrictTransportSecurity   = "Strict-Transport-Security"
+	HeaderContentSecurityPolicy     = "Content-Security-Policy"
+	HeaderXContentTypeOptions       = "X-Content-Type-Options"
+	HeaderXFrameOptions             = "X-Frame-Options"
+	HeaderReferrerPolicy            = "Referrer-Policy"
+	HeaderPermissionsPolicy         = "Permissions-Policy"
+	HeaderCrossOriginOpenerPolicy   = "Cross-Origin-Opener-Policy"
+	HeaderCrossOriginEmbedderPolicy = "Cross-Origin-Embedder-Policy"
+
+	CSPNoncePlaceholder = "{nonce}"
+
+	cspNonceSize = 16
+	cspNonceKey  = "procyon.web.csp-nonce"
+)
+
+const SecurityHeadersInterceptorPriority = core.PriorityHighest + 10
+
+type SecurityHeaders struct {
+	HSTSMaxAge                int
+	HSTSIncludeSubDomains     bool
+	HSTSPreload               bool
+	ContentSecurityPolicy     string
+	ContentTypeOptions        string
+	FrameOptions              string
+	ReferrerPolicy            string
+	PermissionsPolicy         string
+	CrossOriginOpenerPolicy   string
+	CrossOriginEmbedderPolicy string
+}
+
+func DefaultSecurityHeaders() SecurityHeaders {
+	return SecurityHeaders{
+		HSTSMaxAge:              31536000,
+		HSTSIncludeSubDomains:   true,
+		ContentSecurityPolicy:   "default-src 'self'",
+		ContentTypeOptions:      "nosniff",
+		FrameOptions:            "DENY",
+		ReferrerPolicy:          "no-referrer",
+		CrossOriginOpenerPolicy: "same-origin",
+	}
+}
+
+func (headers SecurityHeaders) WithHSTS(maxAge int, includeSubDomains bool, preload bool) SecurityHeaders {
+	headers.HSTSMaxAge = maxAge
+	headers.HSTSIncludeSubDomains = includeSubDomains
+	headers.HSTSPreload = preload
+	return headers
+}
+
+func (headers SecurityHeaders) WithContentSecurityPolicy(policy string) SecurityHeaders {
+	headers.ContentSecurityPolicy = policy
+	return headers
+}
+
+func (headers SecurityHeaders) WithContentTypeOptions(contentTypeOptions string) SecurityHeaders {
+	headers.ContentTypeOptions = contentTypeOptions
+	return headers
+}
+
+func (headers SecurityHeaders) WithFrameOptions(frameOptions string) SecurityHeaders {
+	headers.FrameOptions = frameOptions
+	return headers
+}
+
+func (headers SecurityHeaders) WithReferrerPolicy(referrerPolicy string) SecurityHeaders {
+	headers.ReferrerPolicy = referrerPolicy
+	return headers
+}
+
+func (headers SecurityHeaders) WithPermissionsPolicy(permissionsPolicy string) SecurityHeaders {
+	headers.PermissionsPolicy = permissionsPolicy
+	return headers
+}
+
+func (headers SecurityHeaders) WithCrossOriginOpenerPolicy(openerPolicy string) SecurityHeaders {
+	headers.CrossOriginOpenerPolicy = openerPolicy
+	return headers
+}
+
+func (headers SecurityHeaders) WithCrossOriginEmbedderPolicy(embedderPolicy string) SecurityHeaders {
+	headers.CrossOriginEmbedderPolicy = embedderPolicy
+	return headers
+}
+
+func (headers SecurityHeaders) getStrictTransportSecurity() string {
+	if headers.HSTSMaxAge <= 0 {
+		return ""
+	}
+
+	value := "max-age=" + strconv.Itoa(headers.HSTSMaxAge)
+	if headers.HSTSIncludeSubDomains {
+		value += "; includeSubDomains"
+	}
+
+	if headers.HSTSPreload {
+		value += "; preload"
+	}
+	return value
+}
+
+func (ctx *WebRequestContext) GetCSPNonce() string {
+	nonce, _ := ctx.Get(cspNonceKey).(string)
+	return nonce
+}
+
+func (ctx *WebRequestContext) RelaxSecurityHeader(name string, value string) {
+	if value == "" {
+		ctx.fastHttpRequestContext.Response.Header.Del(name)
+		return
+	}
+	ctx.fastHttpRequestContext.Response.Header.Set(name, value)
+}
+
+type securityHeadersOverride struct {
+	pattern string
+	headers SecurityHeaders
+}
+
+type SecurityHeadersInterceptor struct {
+	enabled   bool
+	headers   SecurityHeaders
+	overrides []securityHeadersOverride
+}
+
+func NewSecurityHeadersInterceptor(properties *SecurityHeadersProperties) SecurityHeadersInterceptor {
+	interceptor := SecurityHeadersInterceptor{
+		enabled:   true,
+		headers:   DefaultSecurityHeaders(),
+		overrides: make([]securityHeadersOverride, 0),
+	}
+
+	if properties == nil {
+		return interceptor
+	}
+
+	interceptor.enabled = properties.Enabled
+	interceptor.headers = SecurityHeaders{
+		HSTSMaxAge:                properties.HSTSMaxAge,
+		HSTSIncludeSubDomains:     properties.HSTSIncludeSubDomains,
+		HSTSPreload:               properties.HSTSPreload,
+		ContentSecurityPolicy:     properties.ContentSecurityPolicy,
+		ContentTypeOptions:        properties.ContentTypeOptions,
+		FrameOptions:              properties.FrameOptions,
+		ReferrerPolicy:            properties.ReferrerPolicy,
+		PermissionsPolicy:         properties.PermissionsPolicy,
+		CrossOriginOpenerPolicy:   properties.CrossOriginOpenerPolicy,
+		CrossOriginEmbedderPolicy: properties.CrossOriginEmbedderPolicy,
+	}
+	return interceptor
+}
+
+func (interceptor SecurityHeadersInterceptor) WithHeaders(headers SecurityHeaders) SecurityHeadersInterceptor {
+	interceptor.headers = headers
+	return interceptor
+}
+
+func (interceptor SecurityHeadersInterceptor) WithPathHeaders(pattern string, headers SecurityHeaders) SecurityHeadersInterceptor {
+	if pattern == "" {
+		panic("Pattern must not be empty")
+	}
+
+	overrides := make([]securityHeadersOverride, len(interceptor.overrides), len(interceptor.overrides)+1)
+	copy(overrides, interceptor.overrides)
+	interceptor.overrides = append(overrides, securityHeadersOverride{pattern, headers})
+	return interceptor
+}
+
+func (interceptor SecurityHeadersInterceptor) GetHeaders() SecurityHeaders {
+	return interceptor.headers
+}
+
+func (interceptor SecurityHeadersInterceptor) GetPriority() core.PriorityValue {
+	return SecurityHeadersInterceptorPriority
+}
+
+func (interceptor SecurityHeadersInterceptor) HandleBefore(ctx *WebRequestContext) {
+	if !interceptor.enabled {
+		return
+	}
+
+	headers := interceptor.getHeaders(ctx.GetPath())
+	responseHeader := &ctx.fastHttpRequestContext.Response.Header
+
+	if ctx.Scheme() == "https" {
+		if value := headers.getStrictTransportSecurity(); value != "" {
+			responseHeader.Set(HeaderStrictTransportSecurity, value)
+		}
+	}
+
+	if policy := headers.ContentSecurityPolicy; policy != "" {
+		if strings.Contains(policy, CSPNoncePlaceholder) {
+			nonce := generateRandomToken(cspNonceSize)
+			ctx.Put(cspNonceKey, nonce)
+			policy = strings.ReplaceAll(policy, CSPNoncePlaceholder, nonce)
+		}
+		responseHeader.Set(HeaderContentSecurityPolicy, policy)
+	}
+
+	setHeaderIfNotEmpty := func(name string, value string) {
+		if value != "" {
+			responseHeader.Set(name, value)
+		}
+	}
+
+	setHeaderIfNotEmpty(HeaderXContentTypeOptions, headers.ContentTypeOptions)
+	setHeaderIfNotEmpty(HeaderXFrameOptions, headers.FrameOptions)
+	setHeaderIfNotEmpty(HeaderReferrerPolicy, headers.ReferrerPolicy)
+	setHeaderIfNotEmpty(HeaderPermissionsPolicy, headers.PermissionsPolicy)
+	setHeaderIfNotEmpty(HeaderCrossOriginOpenerPolicy, headers.CrossOriginOpenerPolicy)
+	setHeaderIfNotEmpty(HeaderCrossOriginEmbedderPolicy, headers.CrossOriginEmbedderPolicy)
+}
+
+func (interceptor SecurityHeadersInterceptor) getHeaders(requestPath string) SecurityHeaders {
+	for _, override := range interceptor.overrides {
+		if matchPathPattern(override.pattern, requestPath) {
+			return override.headers
+		}
+	}
+	return interceptor.headers
+}
@@ -0,0 +1,94 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type testSecurityHeadersController struct {
+}
+
+func (controller testSecurityHeadersController) RegisterHandlers(registry HandlerRegistry) {
+	registry.Register(
+		Get(controller.getPage, Path("/page")),
+		Get(controller.getPage, Path("/embed/widget")),
+		Get(controller.getFramedPage, Path("/framed")),
+	)
+}
+
+func (controller testSecurityHeadersController) getPage(ctx *WebRequestContext) {
+	ctx.Ok().SetModel(ctx.GetCSPNonce())
+}
+
+func (controller testSecurityHeadersController) getFramedPage(ctx *WebRequestContext) {
+	ctx.RelaxSecurityHeader(HeaderXFrameOptions, "SAMEORIGIN")
+	ctx.RelaxSecurityHeader(HeaderCrossOriginOpenerPolicy, "")
+	ctx.Ok().SetModel("framed")
+}
+
+func TestSecurityHeadersInterceptor(t *testing.T) {
+	interceptor := NewSecurityHeadersInterceptor(nil).
+		WithHeaders(DefaultSecurityHeaders().
+			WithContentSecurityPolicy("script-src 'self' 'nonce-{nonce}'").
+			WithPermissionsPolicy("camera=(), geolocation=()").
+			WithCrossOriginEmbedderPolicy("require-corp")).
+		WithPathHeaders("/embed/**", DefaultSecurityHeaders().WithFrameOptions(""))
+	router := NewRouter(
+		WithControllers(testSecurityHeadersController{}),
+		WithInterceptors(interceptor),
+		WithTrustedProxies("0.0.0.0/32"),
+	)
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/page", nil, "")
+	nonce := string(response.Body())
+	assert.NotEmpty(t, nonce)
+	assert.Equal(t, "script-src 'self' 'nonce-"+nonce+"'", string(response.Header.Peek(HeaderContentSecurityPolicy)))
+	assert.Equal(t, "nosniff", string(response.Header.Peek(HeaderXContentTypeOptions)))
+	assert.Equal(t, "DENY", string(response.Header.Peek(HeaderXFrameOptions)))
+	assert.Equal(t, "no-referrer", string(response.Header.Peek(HeaderReferrerPolicy)))
+	assert.Equal(t, "camera=(), geolocation=()", string(response.Header.Peek(HeaderPermissionsPolicy)))
+	assert.Equal(t, "same-origin", string(response.Header.Peek(HeaderCrossOriginOpenerPolicy)))
+	assert.Equal(t, "require-corp", string(response.Header.Peek(HeaderCrossOriginEmbedderPolicy)))
+	assert.Empty(t, response.Header.Peek(HeaderStrictTransportSecurity))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/page", nil, "")
+	assert.NotEqual(t, nonce, string(response.Body()))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/page", map[string]string{HeaderXForwardedProto: "https"}, "")
+	assert.Equal(t, "max-age=31536000; includeSubDomains", string(response.Header.Peek(HeaderStrictTransportSecurity)))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/embed/widget", nil, "")
+	assert.Empty(t, response.Body())
+	assert.Empty(t, response.Header.Peek(HeaderXFrameOptions))
+	assert.Equal(t, "default-src 'self'", string(response.Header.Peek(HeaderContentSecurityPolicy)))
+
+	response = serveTestAdapterRequest(router, http.MethodGet, "/framed", nil, "")
+	assert.Equal(t, "SAMEORIGIN", string(response.Header.Peek(HeaderXFrameOptions)))
+	assert.Empty(t, response.Header.Peek(HeaderCrossOriginOpenerPolicy))
+}
+
+func TestSecurityHeadersInterceptor_Properties(t *testing.T) {
+	interceptor := NewSecurityHeadersInterceptor(&SecurityHeadersProperties{
+		Enabled:        true,
+		HSTSMaxAge:     600,
+		HSTSPreload:    true,
+		FrameOptions:   "SAMEORIGIN",
+		ReferrerPolicy: "strict-origin",
+	})
+	assert.Equal(t, "max-age=600; preload", interceptor.GetHeaders().getStrictTransportSecurity())
+
+	router := NewRouter(WithControllers(testSecurityHeadersController{}), WithInterceptors(interceptor))
+	response := serveTestAdapterRequest(router, http.MethodGet, "/page", nil, "")
+	assert.Equal(t, "SAMEORIGIN", string(response.Header.Peek(HeaderXFrameOptions)))
+	assert.Equal(t, "strict-origin", string(response.Header.Peek(HeaderReferrerPolicy)))
+	assert.Empty(t, response.Header.Peek(HeaderContentSecurityPolicy))
+
+	router = NewRouter(
+		WithControllers(testSecurityHeadersController{}),
+		WithInterceptors(NewSecurityHeadersInterceptor(&SecurityHeadersProperties{FrameOptions: "DENY"})),
+	)
+	response = serveTestAdapterRequest(router, http.MethodGet, "/page", nil, "")
+	assert.False(t, strings.Contains(response.Header.String(), HeaderXFrameOptions))
+}
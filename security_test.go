@@ -0,0 +1,183 @@
+package web
+
+import (
+	json "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type testSecuredController struct {
+}
+
+func (controller testSecuredController) RegisterHandlers(registry HandlerRegistry) {
+	registry.Register(
+		Get(controller.ok, Path("/orders")),
+		Post(controller.ok, Path("/orders"), Secured(Scope("orders:write"))),
+		Delete(controller.ok, Path("/orders/:id"), Secured(RoleAny("admin", "ops"))),
+		Get(controller.ok, Path("/admin/stats")),
+		Get(controller.ok, Path("/public/info"), Secured(PermitAll())),
+	)
+}
+
+func (controller testSecuredController) ok(ctx *WebRequestContext) {
+	ctx.Ok().SetModel("ok")
+}
+
+func testSecurityTokenValidator(token string) (*Authentication, error) {
+	switch token {
+	case "writer":
+		return NewAuthentication(SimplePrincipal{"writer"}, "orders:write"), nil
+	case "admin":
+		return NewAuthentication(SimplePrincipal{"admin"}, RolePrefix+"admin", ScopePrefix+"orders:write"), nil
+	case "reader":
+		return NewAuthentication(SimplePrincipal{"reader"}, "orders:read"), nil
+	}
+	return nil, ErrInvalidToken
+}
+
+func newTestSecuredRouter() *ProcyonRouter {
+	return NewRouter(
+		WithControllers(testSecuredController{}),
+		WithAuthenticators(NewBearerAuthenticator(testSecurityTokenValidator)),
+		WithSecurityRules(
+			NewSecurityRule("/admin/**", RoleAll("admin")),
+			NewSecurityRule("/orders/**", Authenticated()).WithMethods(RequestMethodDelete),
+		),
+	)
+}
+
+func serveTestSecuredRequest(router *ProcyonRouter, method string, uri string, token string) int {
+	headers := map[string]string{}
+	if token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+	return serveTestAdapterRequest(router, method, uri, headers, "").StatusCode()
+}
+
+func TestSecured_HandlerRequirements(t *testing.T) {
+	router := newTestSecuredRouter()
+
+	assert.Equal(t, http.StatusOK, serveTestSecuredRequest(router, http.MethodGet, "/orders", ""))
+	assert.Equal(t, http.StatusOK, serveTestSecuredRequest(router, http.MethodPost, "/orders", "writer"))
+	assert.Equal(t, http.StatusOK, serveTestSecuredRequest(router, http.MethodPost, "/orders", "admin"))
+	assert.Equal(t, http.StatusForbidden, serveTestSecuredRequest(router, http.MethodPost, "/orders", "reader"))
+
+	response := serveTestAdapterRequest(router, http.MethodPost, "/orders", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode())
+	assert.Equal(t, `Bearer realm="procyon"`, string(response.Header.Peek("WWW-Authenticate")))
+
+	assert.Equal(t, http.StatusOK, serveTestSecuredRequest(router, http.MethodDelete, "/orders/5", "admin"))
+	assert.Equal(t, http.StatusForbidden, serveTestSecuredRequest(router, http.MethodDelete, "/orders/5", "writer"))
+	assert.Equal(t, http.StatusOK, serveTestSecuredRequest(router, http.MethodGet, "/public/info", ""))
+}
+
+func TestSecured_RuleTable(t *testing.T) {
+	router := newTestSecuredRouter()
+
+	assert.Equal(t, http.StatusUnauthorized, serveTestSecuredRequest(router, http.MethodGet, "/admin/stats", ""))
+	assert.Equal(t, http.StatusForbidden, serveTestSecuredRequest(router, http.MethodGet, "/admin/stats", "writer"))
+	assert.Equal(t, http.StatusOK, serveTestSecuredRequest(router, http.MethodGet, "/admin/stats", "admin"))
+}
+
+type testSecurityAfterInterceptor struct {
+}
+
+func (interceptor testSecurityAfterInterceptor) HandleAfter(ctx *WebRequestContext) {
+	ctx.AddResponseHeader("X-Handled-After", "true")
+}
+
+func TestSecured_DeniedRequestSkipsAfterInterceptors(t *testing.T) {
+	router := NewRouter(
+		WithControllers(testSecuredController{}),
+		WithAuthenticators(NewBearerAuthenticator(testSecurityTokenValidator)),
+		WithInterceptors(testSecurityAfterInterceptor{}),
+	)
+
+	response := serveTestAdapterRequest(router, http.MethodPost, "/orders", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode())
+	assert.Empty(t, response.Header.Peek("X-Handled-After"))
+
+	response = serveTestAdapterRequest(router, http.MethodPost, "/orders", map[string]string{"Authorization": "Bearer writer"}, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+	assert.Equal(t, "true", string(response.Header.Peek("X-Handled-After")))
+}
+
+func TestSecured_RulesRegisteredAfterFirstRequest(t *testing.T) {
+	router := newTestSecuredRouter()
+	assert.Equal(t, http.StatusOK, serveTestSecuredRequest(router, http.MethodGet, "/orders", ""))
+
+	router.securityRuleRegistry.RegisterSecurityRule(NewSecurityRule("/orders", Authenticated()).WithMethods(RequestMethodGet))
+	assert.Equal(t, http.StatusUnauthorized, serveTestSecuredRequest(router, http.MethodGet, "/orders", ""))
+	assert.Equal(t, http.StatusOK, serveTestSecuredRequest(router, http.MethodGet, "/orders", "reader"))
+}
+
+type testMountedSecuredController struct {
+}
+
+func (controller testMountedSecuredController) RegisterHandlers(registry HandlerRegistry) {
+	registry.Register(Mount("/legacy", http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(request.URL.Path))
+	}))...)
+	registry.Register(Get(testSecuredController{}.ok, Path("/orders/:id")))
+}
+
+func TestSecured_RulesMatchRequestPathOfWildcardRoutes(t *testing.T) {
+	router := NewRouter(
+		WithControllers(testMountedSecuredController{}),
+		WithAuthenticators(NewBearerAuthenticator(testSecurityTokenValidator)),
+		WithSecurityRules(
+			NewSecurityRule("/legacy/admin/**", Authenticated()),
+			NewSecurityRule("/orders/7", RoleAll("admin")),
+		),
+	)
+
+	assert.Equal(t, http.StatusOK, serveTestSecuredRequest(router, http.MethodGet, "/legacy/public", ""))
+	assert.Equal(t, http.StatusUnauthorized, serveTestSecuredRequest(router, http.MethodGet, "/legacy/admin/users", ""))
+	assert.Equal(t, http.StatusUnauthorized, serveTestSecuredRequest(router, http.MethodPost, "/legacy/admin", ""))
+	assert.Equal(t, http.StatusOK, serveTestSecuredRequest(router, http.MethodGet, "/legacy/admin/users", "reader"))
+	assert.Equal(t, http.StatusOK, serveTestSecuredRequest(router, http.MethodGet, "/legacy/public", ""))
+
+	assert.Equal(t, http.StatusOK, serveTestSecuredRequest(router, http.MethodGet, "/orders/5", ""))
+	assert.Equal(t, http.StatusForbidden, serveTestSecuredRequest(router, http.MethodGet, "/orders/7", "reader"))
+	assert.Equal(t, http.StatusOK, serveTestSecuredRequest(router, http.MethodGet, "/orders/7", "admin"))
+}
+
+func TestSecured_WithoutAuthenticationInterceptor(t *testing.T) {
+	router := NewRouter(WithControllers(testSecuredController{}))
+
+	response := serveTestAdapterRequest(router, http.MethodPost, "/orders", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode())
+	assert.Empty(t, response.Header.Peek("WWW-Authenticate"))
+
+	ctx := NewWebRequestContext(RequestMethodPost, "/orders",
+		ContextAuthentication(NewAuthentication(SimplePrincipal{"writer"}, "orders:write")),
+	)
+	ctx.handlerChain.securityRequirements = []SecurityRequirement{Scope("orders:write")}
+	assert.True(t, ctx.authorize())
+}
+
+func TestProcyonRouter_GetRoutes(t *testing.T) {
+	routes := newTestSecuredRouter().GetRoutes()
+	assert.Equal(t, []RouteInfo{
+		{Method: RequestMethodGet, Pattern: "/admin/stats", Security: []string{"roleAll(admin)"}},
+		{Method: RequestMethodGet, Pattern: "/orders", Security: []string{}},
+		{Method: RequestMethodPost, Pattern: "/orders", Security: []string{"scope(orders:write)"}},
+		{Method: RequestMethodDelete, Pattern: "/orders/:id", Security: []string{"authenticated", "roleAny(admin,ops)"}},
+		{Method: RequestMethodGet, Pattern: "/public/info", Security: []string{"permitAll"}},
+	}, routes)
+}
+
+func TestRoutesController(t *testing.T) {
+	securedRouter := newTestSecuredRouter()
+	controller := NewRoutesController(securedRouter.handlerMapping.(RequestHandlerMapping), securedRouter.securityRuleRegistry)
+	router := NewRouter(WithManagementControllers(controller))
+
+	response := serveTestAdapterRequest(router, http.MethodGet, "/routes", nil, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode())
+
+	routes := make([]RouteInfo, 0)
+	assert.Nil(t, json.Unmarshal(response.Body(), &routes))
+	assert.Len(t, routes, 5)
+	assert.Equal(t, []string{"roleAll(admin)"}, routes[0].Security)
+}
@@ -1,11 +1,16 @@
 package web
 
 import (
+	"errors"
 	"github.com/google/uuid"
+	"github.com/procyon-projects/goo"
 	"github.com/procyon-projects/procyon-configure"
 	"github.com/procyon-projects/procyon-context"
+	peas "github.com/procyon-projects/procyon-peas"
 	"github.com/valyala/fasthttp"
 	"strconv"
+	"sync"
+	"time"
 )
 
 type Server interface {
@@ -17,9 +22,16 @@ type Server interface {
 
 const DefaultWebServerPort uint = 8080
 
+var ErrServerShutdownTimeout = errors.New("server shutdown timed out")
+
 type ProcyonWebServer struct {
-	router     Router
-	properties *configure.WebServerProperties
+	router                  Router
+	properties              *configure.WebServerProperties
+	healthIndicatorRegistry HealthIndicatorRegistry
+	readinessDrain          time.Duration
+	maxRequestBodySize      int
+	server                  *fasthttp.Server
+	mu                      sync.Mutex
 }
 
 func (server *ProcyonWebServer) SetProperties(properties *configure.WebServerProperties) {
@@ -27,7 +39,16 @@ func (server *ProcyonWebServer) SetProperties(properties *configure.WebServerPro
 }
 
 func (server *ProcyonWebServer) Run() error {
-	return fasthttp.ListenAndServe(":"+strconv.Itoa(int(server.GetPort())), server.Handle)
+	fastHttpServer := &fasthttp.Server{
+		Handler:            server.Handle,
+		MaxRequestBodySize: server.maxRequestBodySize,
+	}
+
+	server.mu.Lock()
+	server.server = fastHttpServer
+	server.mu.Unlock()
+
+	return fastHttpServer.ListenAndServe(":" + strconv.Itoa(int(server.GetPort())))
 }
 
 func (server *ProcyonWebServer) Handle(ctx *fasthttp.RequestCtx) {
@@ -35,7 +56,25 @@ func (server *ProcyonWebServer) Handle(ctx *fasthttp.RequestCtx) {
 }
 
 func (server *ProcyonWebServer) Stop() error {
-	return nil
+	if server.healthIndicatorRegistry != nil {
+		server.healthIndicatorRegistry.SetReady(false)
+	}
+
+	server.mu.Lock()
+	fastHttpServer := server.server
+	server.mu.Unlock()
+
+	if fastHttpServer == nil {
+		return nil
+	}
+
+	drainReadiness(server.readinessDrain)
+
+	var timeout uint
+	if server.properties != nil {
+		timeout = server.properties.ShutdownTimeout
+	}
+	return shutdownWithTimeout(fastHttpServer.Shutdown, timeout)
 }
 
 func (server *ProcyonWebServer) GetPort() uint {
@@ -48,13 +87,63 @@ func (server *ProcyonWebServer) GetPort() uint {
 	return port
 }
 
-func newProcyonWebServer(ctx context.ApplicationContext) Server {
+func newWebServer(router *ProcyonRouter, peaFactory peas.ConfigurablePeaFactory) Server {
+	var healthIndicatorRegistry HealthIndicatorRegistry
+	registry, _ := peaFactory.GetPeaByType(goo.GetType((*HealthIndicatorRegistry)(nil)))
+	if registry != nil {
+		healthIndicatorRegistry = registry.(HealthIndicatorRegistry)
+	}
+
+	var readinessDrain time.Duration
+	shutdownProperties, _ := peaFactory.GetPeaByType(goo.GetType((*ShutdownProperties)(nil)))
+	if shutdownProperties != nil {
+		readinessDrain = time.Duration(shutdownProperties.(*ShutdownProperties).ReadinessDrain) * time.Second
+	}
+
+	backendProperties, _ := peaFactory.GetPeaByType(goo.GetType((*ServerBackendProperties)(nil)))
+	if backendProperties != nil && backendProperties.(*ServerBackendProperties).Type == ServerBackendNetHttp {
+		server := NewNetHttpWebServer(router, backendProperties.(*ServerBackendProperties))
+		server.healthIndicatorRegistry = healthIndicatorRegistry
+		server.readinessDrain = readinessDrain
+		return server
+	}
+
 	server := &ProcyonWebServer{
-		router: NewProcyonRouter(ctx.(context.ConfigurableApplicationContext)),
+		router:                  router,
+		healthIndicatorRegistry: healthIndicatorRegistry,
+		readinessDrain:          readinessDrain,
+	}
+
+	if backendProperties != nil {
+		server.maxRequestBodySize = backendProperties.(*ServerBackendProperties).MaxRequestBodySize
 	}
 	return server
 }
 
+func drainReadiness(readinessDrain time.Duration) {
+	if readinessDrain > 0 {
+		time.Sleep(readinessDrain)
+	}
+}
+
+func shutdownWithTimeout(shutdown func() error, timeout uint) error {
+	if timeout == 0 {
+		return shutdown()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- shutdown()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(time.Duration(timeout) * time.Second):
+		return ErrServerShutdownTimeout
+	}
+}
+
 func NewProcyonWebServerForBenchmark(handlerRegistry SimpleHandlerRegistry) *ProcyonWebServer {
 	appId := uuid.New()
 	contextId := uuid.New()
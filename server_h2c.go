@@ -0,0 +1,15 @@
+//go:build go1.24
+// +build go1.24
+
+package web
+
+import "net/http"
+
+func enableH2C(server *http.Server) error {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	server.Protocols = protocols
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !go1.24
+// +build !go1.24
+
+package web
+
+import (
+	"errors"
+	"net/http"
+)
+
+func enableH2C(server *http.Server) error {
+	return errors.New("h2c requires go1.24 or later")
+}
@@ -0,0 +1,304 @@
+package web
+
+import (
+	"bufio"
+	stdcontext "context"
+	"crypto/tls"
+	"errors"
+	configure "github.com/procyon-projects/procyon-configure"
+	"github.com/valyala/fasthttp"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	ServerBackendFastHttp = "fasthttp"
+	ServerBackendNetHttp  = "net/http"
+)
+
+const (
+	netHttpUserValueKey       = "procyon.web.net-http"
+	hijackHandlerUserValueKey = "procyon.web.hijack-handler"
+)
+
+var errRequestBodyTooLarge = errors.New("request body too large")
+
+var hopByHopHeaders = map[string]bool{
+	fasthttp.HeaderConnection:       true,
+	fasthttp.HeaderKeepAlive:        true,
+	fasthttp.HeaderTransferEncoding: true,
+	fasthttp.HeaderUpgrade:          true,
+}
+
+type NetHttpWebServer struct {
+	router                  *ProcyonRouter
+	properties              *configure.WebServerProperties
+	backendProperties       *ServerBackendProperties
+	healthIndicatorRegistry HealthIndicatorRegistry
+	readinessDrain          time.Duration
+	server                  *http.Server
+	mu                      sync.Mutex
+}
+
+func NewNetHttpWebServer(router *ProcyonRouter, backendProperties *ServerBackendProperties) *NetHttpWebServer {
+	if backendProperties == nil {
+		backendProperties = newServerBackendProperties()
+	}
+
+	return &NetHttpWebServer{
+		router:            router,
+		backendProperties: backendProperties,
+	}
+}
+
+func (server *NetHttpWebServer) SetProperties(properties *configure.WebServerProperties) {
+	server.properties = properties
+}
+
+func (server *NetHttpWebServer) GetPort() uint {
+	if server.properties == nil {
+		return DefaultWebServerPort
+	}
+	return server.properties.Port
+}
+
+func (server *NetHttpWebServer) Run() error {
+	httpServer := &http.Server{
+		Addr:    ":" + strconv.Itoa(int(server.GetPort())),
+		Handler: NewHTTPHandler(server.router).WithMaxRequestBodySize(server.backendProperties.MaxRequestBodySize),
+	}
+
+	if server.backendProperties.H2C {
+		if err := enableH2C(httpServer); err != nil {
+			return err
+		}
+	}
+
+	server.mu.Lock()
+	server.server = httpServer
+	server.mu.Unlock()
+
+	var err error
+	if server.backendProperties.TLSCertFile != "" {
+		httpServer.TLSConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+		err = httpServer.ListenAndServeTLS(server.backendProperties.TLSCertFile, server.backendProperties.TLSKeyFile)
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (server *NetHttpWebServer) Stop() error {
+	if server.healthIndicatorRegistry != nil {
+		server.healthIndicatorRegistry.SetReady(false)
+	}
+
+	server.mu.Lock()
+	httpServer := server.server
+	server.mu.Unlock()
+
+	if httpServer == nil {
+		return nil
+	}
+
+	drainReadiness(server.readinessDrain)
+
+	shutdownContext := stdcontext.Background()
+	if server.properties != nil && server.properties.ShutdownTimeout != 0 {
+		var cancel stdcontext.CancelFunc
+		shutdownContext, cancel = stdcontext.WithTimeout(shutdownContext, time.Duration(server.properties.ShutdownTimeout)*time.Second)
+		defer cancel()
+	}
+
+	return httpServer.Shutdown(shutdownContext)
+}
+
+type HTTPHandler struct {
+	router             *ProcyonRouter
+	maxRequestBodySize int
+}
+
+func NewHTTPHandler(router *ProcyonRouter) HTTPHandler {
+	if router == nil {
+		panic("Router must not be null")
+	}
+
+	return HTTPHandler{
+		router:             router,
+		maxRequestBodySize: fasthttp.DefaultMaxRequestBodySize,
+	}
+}
+
+func (handler HTTPHandler) WithMaxRequestBodySize(maxRequestBodySize int) HTTPHandler {
+	if maxRequestBodySize > 0 {
+		handler.maxRequestBodySize = maxRequestBodySize
+	}
+	return handler
+}
+
+func (handler HTTPHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	fastHttpRequest := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(fastHttpRequest)
+
+	if err := convertHTTPRequest(request, fastHttpRequest, handler.maxRequestBodySize); err != nil {
+		if err == errRequestBodyTooLarge {
+			http.Error(writer, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	requestCtx := &fasthttp.RequestCtx{}
+	requestCtx.Init(fastHttpRequest, remoteAddress(request.RemoteAddr), nil)
+	requestCtx.SetUserValue(netHttpUserValueKey, true)
+
+	handler.router.Route(requestCtx)
+
+	if hijackHandler, ok := requestCtx.UserValue(hijackHandlerUserValueKey).(fasthttp.HijackHandler); ok {
+		serveHijackedConnection(writer, &requestCtx.Response, hijackHandler)
+		return
+	}
+
+	writeHTTPResponse(writer, &requestCtx.Response)
+}
+
+func convertHTTPRequest(request *http.Request, fastHttpRequest *fasthttp.Request, maxBodySize int) error {
+	fastHttpRequest.Header.SetMethod(request.Method)
+	fastHttpRequest.SetRequestURI(request.URL.RequestURI())
+	fastHttpRequest.Header.SetHost(request.Host)
+	if request.TLS != nil {
+		fastHttpRequest.URI().SetScheme("https")
+	}
+
+	for key, values := range request.Header {
+		if key == fasthttp.HeaderContentLength || key == fasthttp.HeaderTransferEncoding {
+			continue
+		}
+
+		for _, value := range values {
+			fastHttpRequest.Header.Add(key, value)
+		}
+	}
+
+	if request.Body != nil && request.Body != http.NoBody {
+		body, err := ioutil.ReadAll(io.LimitReader(request.Body, int64(maxBodySize)+1))
+		if err != nil {
+			return err
+		}
+
+		if len(body) > maxBodySize {
+			return errRequestBodyTooLarge
+		}
+		fastHttpRequest.SetBody(body)
+	}
+
+	return nil
+}
+
+func remoteAddress(address string) net.Addr {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil
+	}
+
+	portNumber, _ := strconv.Atoi(port)
+	return &net.TCPAddr{
+		IP:   net.ParseIP(host),
+		Port: portNumber,
+	}
+}
+
+func writeHTTPResponse(writer http.ResponseWriter, response *fasthttp.Response) {
+	header := writer.Header()
+	response.Header.VisitAll(func(key, value []byte) {
+		if !hopByHopHeaders[string(key)] {
+			header.Add(string(key), string(value))
+		}
+	})
+
+	writer.WriteHeader(response.StatusCode())
+	if response.IsBodyStream() {
+		_ = response.BodyWriteTo(flushWriter{writer})
+		return
+	}
+
+	_, _ = writer.Write(response.Body())
+}
+
+func serveHijackedConnection(writer http.ResponseWriter, response *fasthttp.Response, hijackHandler fasthttp.HijackHandler) {
+	hijacker, ok := writer.(http.Hijacker)
+	if !ok {
+		http.Error(writer, "connection cannot be hijacked", http.StatusInternalServerError)
+		return
+	}
+
+	conn, bufferedConn, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+
+	bufferedWriter := bufferedConn.Writer
+	_, _ = bufferedWriter.WriteString("HTTP/1.1 " + strconv.Itoa(response.StatusCode()) + " " + http.StatusText(response.StatusCode()) + "\r\n")
+	response.Header.VisitAll(func(key, value []byte) {
+		switch string(key) {
+		case fasthttp.HeaderContentLength, fasthttp.HeaderContentType:
+			return
+		}
+		_, _ = bufferedWriter.Write(key)
+		_, _ = bufferedWriter.WriteString(": ")
+		_, _ = bufferedWriter.Write(value)
+		_, _ = bufferedWriter.WriteString("\r\n")
+	})
+	_, _ = bufferedWriter.WriteString("\r\n")
+
+	if err = bufferedWriter.Flush(); err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	hijackHandler(&hijackedConn{conn, bufferedConn.Reader})
+}
+
+type hijackedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (conn *hijackedConn) Read(data []byte) (int, error) {
+	return conn.reader.Read(data)
+}
+
+type flushWriter struct {
+	writer http.ResponseWriter
+}
+
+func (writer flushWriter) Write(data []byte) (int, error) {
+	n, err := writer.writer.Write(data)
+	if flusher, ok := writer.writer.(http.Flusher); ok && err == nil {
+		flusher.Flush()
+	}
+	return n, err
+}
+
+var _ io.Writer = flushWriter{}
+
+func (ctx *WebRequestContext) hijack(handler fasthttp.HijackHandler) {
+	if ctx.fastHttpRequestContext.UserValue(netHttpUserValueKey) != nil {
+		ctx.fastHttpRequestContext.SetUserValue(hijackHandlerUserValueKey, handler)
+		return
+	}
+	ctx.fastHttpRequestContext.Hijack(handler)
+}
@@ -0,0 +1,162 @@
+package web
+
+import (
+	"bufio"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testNetHttpController struct {
+}
+
+func (controller testNetHttpController) RegisterHandlers(registry HandlerRegistry) {
+	registry.Register(
+		Get(controller.getGreeting, Path("/greetings/:name")),
+		Head(controller.getGreeting, Path("/greetings/:name")),
+		Post(controller.echo, Path("/echo")),
+		Get(controller.events, Path("/events")),
+		WebSocket(controller.chat, Path("/chat")),
+	)
+}
+
+func (controller testNetHttpController) getGreeting(ctx *WebRequestContext) {
+	name, _ := ctx.GetPathVariable("name")
+	query, _ := ctx.GetRequestParameter("suffix")
+	ctx.AddResponseHeader("X-Greeting", name)
+	ctx.Ok().SetModel(map[string]string{"message": "hello " + name + query}).SetResponseContentType(MediaTypeApplicationJson)
+}
+
+func (controller testNetHttpController) echo(ctx *WebRequestContext) {
+	header, _ := ctx.GetRequestHeader("X-Echo")
+	ctx.Created("/echo").SetModel(header + ":" + string(ctx.GetRequestBody()))
+}
+
+func (controller testNetHttpController) events(ctx *WebRequestContext) {
+	ctx.StartEventStream(func(stream *EventStream) {
+		_ = stream.Send(ServerSentEvent{Id: "1", Data: "first"})
+		_ = stream.Send(ServerSentEvent{Id: "2", Data: "second"})
+	}, EventStreamHeartbeat(0))
+}
+
+func (controller testNetHttpController) chat(conn *WebSocketConnection) {
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = conn.WriteMessage(messageType, append([]byte("echo:"), data...))
+	}
+}
+
+func TestHTTPHandler_ServeHTTP(t *testing.T) {
+	server := httptest.NewServer(NewHTTPHandler(NewRouter(WithControllers(testNetHttpController{}))))
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/greetings/procyon?suffix=!")
+	assert.Nil(t, err)
+	body, _ := ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "procyon", response.Header.Get("X-Greeting"))
+	assert.Equal(t, MediaTypeApplicationJsonValue, response.Header.Get("Content-Type"))
+	assert.Equal(t, "{\"message\":\"hello procyon!\"}", string(body))
+
+	request, _ := http.NewRequest(http.MethodPost, server.URL+"/echo", strings.NewReader("payload"))
+	request.Header.Set("X-Echo", "header")
+	response, err = http.DefaultClient.Do(request)
+	assert.Nil(t, err)
+	body, _ = ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, "header:payload", string(body))
+
+	response, err = http.Get(server.URL + "/unknown")
+	assert.Nil(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestHTTPHandler_ServeHTTPEventStream(t *testing.T) {
+	server := httptest.NewServer(NewHTTPHandler(NewRouter(WithControllers(testNetHttpController{}))))
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/events")
+	assert.Nil(t, err)
+	body, _ := ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, MediaTypeTextEventStreamValue, response.Header.Get("Content-Type"))
+	assert.Equal(t, ":\n\nid: 1\ndata: first\n\nid: 2\ndata: second\n\n", string(body))
+}
+
+func TestHTTPHandler_ServeHTTPWebSocket(t *testing.T) {
+	server := httptest.NewServer(NewHTTPHandler(NewRouter(WithControllers(testNetHttpController{}))))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	request := "GET /chat HTTP/1.1\r\nHost: localhost\r\n"
+	for key, value := range webSocketUpgradeHeaders() {
+		request += key + ": " + value + "\r\n"
+	}
+	_, err = conn.Write([]byte(request + "\r\n"))
+	assert.Nil(t, err)
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+	assert.Equal(t, "websocket", response.Header.Get("Upgrade"))
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", response.Header.Get("Sec-WebSocket-Accept"))
+
+	writeTestWebSocketFrame(t, conn, WebSocketTextMessage, []byte("hello"))
+	opcode, payload := readTestWebSocketFrame(t, reader)
+	assert.Equal(t, byte(WebSocketTextMessage), opcode)
+	assert.Equal(t, "echo:hello", string(payload))
+}
+
+func TestHTTPHandler_ServeHTTPRequestBodyTooLarge(t *testing.T) {
+	server := httptest.NewServer(NewHTTPHandler(NewRouter(WithControllers(testNetHttpController{}))).WithMaxRequestBodySize(4))
+	defer server.Close()
+
+	response, err := http.Post(server.URL+"/echo", "text/plain", strings.NewReader("payload"))
+	assert.Nil(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, http.StatusRequestEntityTooLarge, response.StatusCode)
+
+	response, err = http.Post(server.URL+"/echo", "text/plain", strings.NewReader("pay"))
+	assert.Nil(t, err)
+	body, _ := ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, "4", response.Header.Get("Content-Length"))
+	assert.Equal(t, ":pay", string(body))
+
+	response, err = http.Get(server.URL + "/greetings/procyon")
+	assert.Nil(t, err)
+	body, _ = ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+
+	request, _ := http.NewRequest(http.MethodHead, server.URL+"/greetings/procyon", nil)
+	response, err = http.DefaultClient.Do(request)
+	assert.Nil(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, int64(len(body)), response.ContentLength)
+}
+
+func TestNewNetHttpWebServer(t *testing.T) {
+	server := NewNetHttpWebServer(NewRouter(), nil)
+	assert.Equal(t, DefaultWebServerPort, server.GetPort())
+	assert.False(t, server.backendProperties.H2C)
+	assert.Nil(t, server.Stop())
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/valyala/fasthttp"
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestProcyonWebServer(t *testing.T) {
@@ -25,6 +26,27 @@ func TestProcyonWebServer(t *testing.T) {
 	webServer.Stop()
 }
 
+func TestProcyonWebServer_StopWithReadinessDrain(t *testing.T) {
+	registry := NewSimpleHealthIndicatorRegistry()
+	webServer := &ProcyonWebServer{
+		healthIndicatorRegistry: registry,
+		readinessDrain:          100 * time.Millisecond,
+		server:                  &fasthttp.Server{},
+	}
+
+	readyDuringDrain := make(chan bool, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		readyDuringDrain <- registry.IsReady()
+	}()
+
+	start := time.Now()
+	assert.Nil(t, webServer.Stop())
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	assert.False(t, registry.IsReady())
+	assert.False(t, <-readyDuringDrain)
+}
+
 type mockResponseWriter struct{}
 
 func (m *mockResponseWriter) Header() (h http.Header) {
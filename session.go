@@ -0,0 +1,532 @@
+package web
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	json "github.com/json-iterator/go"
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/valyala/fasthttp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	SessionStoreMemory = "memory"
+	SessionStoreCookie = "cookie"
+
+	DefaultSessionCookieName      = "SESSION"
+	DefaultSessionIdleTimeout     = 30 * time.Minute
+	DefaultSessionAbsoluteTimeout = 24 * time.Hour
+
+	sessionIdSize         = 32
+	sessionStateKey       = "procyon.web.session-state"
+	sessionSweepInterval  = time.Minute
+	maxSessionCookieValue = 4096
+)
+
+const SessionInterceptorPriority = core.PriorityHighest + 50
+
+var (
+	ErrInvalidSession  = errors.New("invalid session")
+	ErrSessionTooLarge = errors.New("session is too large to be stored in a cookie")
+)
+
+type SessionData struct {
+	Id             string                 `json:"id"`
+	Attributes     map[string]interface{} `json:"attributes"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	LastAccessedAt time.Time              `json:"lastAccessedAt"`
+}
+
+func (data *SessionData) copy() *SessionData {
+	copied := *data
+	copied.Attributes = make(map[string]interface{}, len(data.Attributes))
+	for key, value := range data.Attributes {
+		copied.Attributes[key] = value
+	}
+	return &copied
+}
+
+type SessionStore interface {
+	Load(value string) (*SessionData, error)
+	Save(data *SessionData, ttl time.Duration) (string, error)
+	Delete(value string) error
+}
+
+type sessionEntry struct {
+	data      *SessionData
+	expiresAt time.Time
+}
+
+type InMemorySessionStore struct {
+	entries   map[string]*sessionEntry
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+func NewInMemorySessionStore() *InMemorySessionStore {
+	return &InMemorySessionStore{
+		entries:   make(map[string]*sessionEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+func (store *InMemorySessionStore) Load(value string) (*SessionData, error) {
+	now := time.Now()
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	entry, ok := store.entries[value]
+	if !ok {
+		return nil, nil
+	}
+
+	if now.After(entry.expiresAt) {
+		delete(store.entries, value)
+		return nil, nil
+	}
+	return entry.data.copy(), nil
+}
+
+func (store *InMemorySessionStore) Save(data *SessionData, ttl time.Duration) (string, error) {
+	now := time.Now()
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if now.Sub(store.lastSweep) >= sessionSweepInterval {
+		store.sweep(now)
+	}
+
+	store.entries[data.Id] = &sessionEntry{
+		data:      data.copy(),
+		expiresAt: now.Add(ttl),
+	}
+	return data.Id, nil
+}
+
+func (store *InMemorySessionStore) Delete(value string) error {
+	store.mu.Lock()
+	delete(store.entries, value)
+	store.mu.Unlock()
+	return nil
+}
+
+func (store *InMemorySessionStore) sweep(now time.Time) {
+	for key, entry := range store.entries {
+		if now.After(entry.expiresAt) {
+			delete(store.entries, key)
+		}
+	}
+	store.lastSweep = now
+}
+
+func (store *InMemorySessionStore) Len() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return len(store.entries)
+}
+
+type CookieSessionStore struct {
+	ciphers []cipher.AEAD
+}
+
+func NewCookieSessionStore(secrets ...string) CookieSessionStore {
+	store := CookieSessionStore{
+		ciphers: make([]cipher.AEAD, 0, len(secrets)),
+	}
+
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+
+		key := sha256.Sum256([]byte(secret))
+		block, err := aes.NewCipher(key[:])
+		if err != nil {
+			panic(err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			panic(err)
+		}
+		store.ciphers = append(store.ciphers, aead)
+	}
+
+	if len(store.ciphers) == 0 {
+		panic("Session cookie secret must not be empty")
+	}
+	return store
+}
+
+func (store CookieSessionStore) Load(value string) (*SessionData, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidSession
+	}
+
+	for _, aead := range store.ciphers {
+		if len(payload) < aead.NonceSize() {
+			continue
+		}
+
+		nonce, ciphertext := payload[:aead.NonceSize()], payload[aead.NonceSize():]
+		plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+		if err != nil {
+			continue
+		}
+
+		data := &SessionData{}
+		if err = json.Unmarshal(plaintext, data); err != nil {
+			return nil, ErrInvalidSession
+		}
+		return data, nil
+	}
+	return nil, ErrInvalidSession
+}
+
+func (store CookieSessionStore) Save(data *SessionData, ttl time.Duration) (string, error) {
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	aead := store.ciphers[0]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil))
+	if len(value) > maxSessionCookieValue {
+		return "", ErrSessionTooLarge
+	}
+	return value, nil
+}
+
+func (store CookieSessionStore) Delete(value string) error {
+	return nil
+}
+
+type Session struct {
+	data        *SessionData
+	isNew       bool
+	modified    bool
+	invalidated bool
+}
+
+func newSession(now time.Time) *Session {
+	return &Session{
+		data: &SessionData{
+			Id:             generateRandomToken(sessionIdSize),
+			Attributes:     make(map[string]interface{}),
+			CreatedAt:      now,
+			LastAccessedAt: now,
+		},
+		isNew: true,
+	}
+}
+
+func (session *Session) GetId() string {
+	return session.data.Id
+}
+
+func (session *Session) Get(key string) interface{} {
+	return session.data.Attributes[key]
+}
+
+func (session *Session) Put(key string, value interface{}) {
+	if session.invalidated {
+		return
+	}
+
+	session.data.Attributes[key] = value
+	session.modified = true
+}
+
+func (session *Session) Remove(key string) {
+	if _, ok := session.data.Attributes[key]; ok {
+		delete(session.data.Attributes, key)
+		session.modified = true
+	}
+}
+
+func (session *Session) GetAttributeNames() []string {
+	names := make([]string, 0, len(session.data.Attributes))
+	for name := range session.data.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (session *Session) GetCreationTime() time.Time {
+	return session.data.CreatedAt
+}
+
+func (session *Session) GetLastAccessedTime() time.Time {
+	return session.data.LastAccessedAt
+}
+
+func (session *Session) IsNew() bool {
+	return session.isNew
+}
+
+func (session *Session) IsInvalidated() bool {
+	return session.invalidated
+}
+
+func (session *Session) RotateId() {
+	if session.invalidated {
+		return
+	}
+
+	session.data.Id = generateRandomToken(sessionIdSize)
+	session.modified = true
+}
+
+func (session *Session) Invalidate() {
+	session.data.Attributes = make(map[string]interface{})
+	session.invalidated = true
+}
+
+type sessionState struct {
+	interceptor SessionInterceptor
+	cookieValue string
+	loadedId    string
+	session     *Session
+}
+
+func (ctx *WebRequestContext) Session() *Session {
+	state, ok := ctx.Get(sessionStateKey).(*sessionState)
+	if !ok {
+		return nil
+	}
+
+	if state.session != nil && !state.session.invalidated {
+		return state.session
+	}
+
+	now := state.interceptor.clock()
+	if state.session == nil && state.cookieValue != "" {
+		data, err := state.interceptor.store.Load(state.cookieValue)
+		if err == nil && data != nil && state.interceptor.isValid(data, now) {
+			data.LastAccessedAt = now
+			state.loadedId = data.Id
+			state.session = &Session{
+				data: data,
+			}
+			return state.session
+		}
+
+		if data != nil {
+			_ = state.interceptor.store.Delete(state.cookieValue)
+		}
+	}
+
+	state.session = newSession(now)
+	return state.session
+}
+
+type SessionInterceptor struct {
+	enabled         bool
+	store           SessionStore
+	cookieName      string
+	cookiePath      string
+	cookieDomain    string
+	cookieSecure    bool
+	cookieHttpOnly  bool
+	cookieSameSite  fasthttp.CookieSameSite
+	idleTimeout     time.Duration
+	absoluteTimeout time.Duration
+	clock           func() time.Time
+}
+
+func NewSessionInterceptor(properties *SessionProperties) SessionInterceptor {
+	interceptor := SessionInterceptor{
+		enabled:         true,
+		store:           NewInMemorySessionStore(),
+		cookieName:      DefaultSessionCookieName,
+		cookiePath:      "/",
+		cookieHttpOnly:  true,
+		cookieSameSite:  fasthttp.CookieSameSiteLaxMode,
+		idleTimeout:     DefaultSessionIdleTimeout,
+		absoluteTimeout: DefaultSessionAbsoluteTimeout,
+		clock:           time.Now,
+	}
+
+	if properties == nil {
+		return interceptor
+	}
+
+	interceptor.enabled = properties.Enabled
+	if !interceptor.enabled {
+		return interceptor
+	}
+
+	switch properties.Store {
+	case "", SessionStoreMemory:
+	case SessionStoreCookie:
+		secrets := strings.Split(properties.Secret, ",")
+		for index, secret := range secrets {
+			secrets[index] = strings.TrimSpace(secret)
+		}
+		interceptor.store = NewCookieSessionStore(secrets...)
+	default:
+		panic("Unsupported session store : " + properties.Store)
+	}
+
+	if properties.CookieName != "" {
+		interceptor.cookieName = properties.CookieName
+	}
+
+	if properties.CookiePath != "" {
+		interceptor.cookiePath = properties.CookiePath
+	}
+
+	interceptor.cookieDomain = properties.CookieDomain
+	interceptor.cookieSecure = properties.CookieSecure
+	interceptor.cookieHttpOnly = properties.CookieHttpOnly
+
+	switch strings.ToLower(properties.CookieSameSite) {
+	case "", "lax":
+	case "strict":
+		interceptor.cookieSameSite = fasthttp.CookieSameSiteStrictMode
+	case "none":
+		interceptor.cookieSameSite = fasthttp.CookieSameSiteNoneMode
+	default:
+		panic("Unsupported session cookie same-site mode : " + properties.CookieSameSite)
+	}
+
+	if properties.IdleTimeout > 0 {
+		interceptor.idleTimeout = time.Duration(properties.IdleTimeout) * time.Second
+	}
+
+	if properties.AbsoluteTimeout > 0 {
+		interceptor.absoluteTimeout = time.Duration(properties.AbsoluteTimeout) * time.Second
+	}
+	return interceptor
+}
+
+func (interceptor SessionInterceptor) WithStore(store SessionStore) SessionInterceptor {
+	if store == nil {
+		panic("Store must not be null")
+	}
+
+	interceptor.store = store
+	return interceptor
+}
+
+func (interceptor SessionInterceptor) WithIdleTimeout(idleTimeout time.Duration) SessionInterceptor {
+	interceptor.idleTimeout = idleTimeout
+	return interceptor
+}
+
+func (interceptor SessionInterceptor) WithAbsoluteTimeout(absoluteTimeout time.Duration) SessionInterceptor {
+	interceptor.absoluteTimeout = absoluteTimeout
+	return interceptor
+}
+
+func (interceptor SessionInterceptor) GetPriority() core.PriorityValue {
+	return SessionInterceptorPriority
+}
+
+func (interceptor SessionInterceptor) HandleBefore(ctx *WebRequestContext) {
+	if !interceptor.enabled {
+		return
+	}
+
+	ctx.Put(sessionStateKey, &sessionState{
+		interceptor: interceptor,
+		cookieValue: string(ctx.fastHttpRequestContext.Request.Header.Cookie(interceptor.cookieName)),
+	})
+}
+
+func (interceptor SessionInterceptor) AfterCompletion(ctx *WebRequestContext) {
+	state, ok := ctx.Get(sessionStateKey).(*sessionState)
+	if !ok || state.session == nil {
+		return
+	}
+
+	session := state.session
+	if state.loadedId != "" && (session.invalidated || session.data.Id != state.loadedId) {
+		_ = interceptor.store.Delete(state.cookieValue)
+	}
+
+	if session.invalidated || (session.isNew && !session.modified) {
+		if state.cookieValue != "" {
+			interceptor.clearCookie(ctx)
+		}
+		return
+	}
+
+	now := interceptor.clock()
+	session.data.LastAccessedAt = now
+
+	value, err := interceptor.store.Save(session.data, interceptor.getTimeToLive(session.data, now))
+	if err != nil {
+		if ctx.router != nil && ctx.router.logger != nil {
+			ctx.router.logger.Error(ctx, "Session could not be saved : "+err.Error())
+		}
+		return
+	}
+
+	if value != state.cookieValue {
+		interceptor.setCookie(ctx, value, time.Time{})
+	}
+}
+
+func (interceptor SessionInterceptor) isValid(data *SessionData, now time.Time) bool {
+	if interceptor.idleTimeout > 0 && now.Sub(data.LastAccessedAt) > interceptor.idleTimeout {
+		return false
+	}
+
+	if interceptor.absoluteTimeout > 0 && now.Sub(data.CreatedAt) > interceptor.absoluteTimeout {
+		return false
+	}
+	return true
+}
+
+func (interceptor SessionInterceptor) getTimeToLive(data *SessionData, now time.Time) time.Duration {
+	ttl := interceptor.idleTimeout
+	if interceptor.absoluteTimeout > 0 {
+		remaining := data.CreatedAt.Add(interceptor.absoluteTimeout).Sub(now)
+		if ttl <= 0 || remaining < ttl {
+			ttl = remaining
+		}
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultSessionIdleTimeout
+	}
+	return ttl
+}
+
+func (interceptor SessionInterceptor) setCookie(ctx *WebRequestContext, value string, expire time.Time) {
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	cookie.SetKey(interceptor.cookieName)
+	cookie.SetValue(value)
+	cookie.SetPath(interceptor.cookiePath)
+	cookie.SetDomain(interceptor.cookieDomain)
+	cookie.SetSecure(interceptor.cookieSecure)
+	cookie.SetHTTPOnly(interceptor.cookieHttpOnly)
+	cookie.SetSameSite(interceptor.cookieSameSite)
+	if !expire.IsZero() {
+		cookie.SetExpire(expire)
+	}
+	ctx.fastHttpRequestContext.Response.Header.SetCookie(cookie)
+}
+
+func (interceptor SessionInterceptor) clearCookie(ctx *WebRequestContext) {
+	interceptor.setCookie(ctx, "", fasthttp.CookieExpireDelete)
+}
@@ -0,0 +1,176 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testSessionController struct {
+}
+
+func (controller testSessionController) RegisterHandlers(registry HandlerRegistry) {
+	registry.Register(
+		Get(controller.getVisits, Path("/visits")),
+		Post(controller.login, Path("/login")),
+		Post(controller.logout, Path("/logout")),
+	)
+}
+
+func (controller testSessionController) getVisits(ctx *WebRequestContext) {
+	session := ctx.Session()
+	visits, _ := session.Get("visits").(string)
+	visits += "+"
+	session.Put("visits", visits)
+	ctx.Ok().SetModel(visits)
+}
+
+func (controller testSessionController) login(ctx *WebRequestContext) {
+	session := ctx.Session()
+	session.RotateId()
+	session.Put("user", "procyon")
+	ctx.Ok().SetModel(session.GetId())
+}
+
+func (controller testSessionController) logout(ctx *WebRequestContext) {
+	ctx.Session().Invalidate()
+	ctx.Ok().SetModel("bye")
+}
+
+func getTestResponseCookie(response *fasthttp.Response, name string) (*fasthttp.Cookie, bool) {
+	cookie := &fasthttp.Cookie{}
+	cookie.SetKey(name)
+	if !response.Header.Cookie(cookie) {
+		return nil, false
+	}
+	return cookie, true
+}
+
+func serveTestSessionRequest(router *ProcyonRouter, method string, uri string, cookieValue string) *fasthttp.Response {
+	headers := map[string]string{}
+	if cookieValue != "" {
+		headers["Cookie"] = DefaultSessionCookieName + "=" + cookieValue
+	}
+	return serveTestAdapterRequest(router, method, uri, headers, "")
+}
+
+func TestSessionInterceptor_InMemoryStore(t *testing.T) {
+	store := NewInMemorySessionStore()
+	interceptor := NewSessionInterceptor(nil).WithStore(store)
+	router := NewRouter(WithControllers(testSessionController{}), WithInterceptors(interceptor))
+
+	response := serveTestSessionRequest(router, http.MethodGet, "/visits", "")
+	assert.Equal(t, "+", string(response.Body()))
+	cookie, ok := getTestResponseCookie(response, DefaultSessionCookieName)
+	assert.True(t, ok)
+	assert.True(t, cookie.HTTPOnly())
+	assert.Equal(t, fasthttp.CookieSameSiteLaxMode, cookie.SameSite())
+	sessionId := string(cookie.Value())
+	assert.Equal(t, 1, store.Len())
+
+	response = serveTestSessionRequest(router, http.MethodGet, "/visits", sessionId)
+	assert.Equal(t, "++", string(response.Body()))
+	_, ok = getTestResponseCookie(response, DefaultSessionCookieName)
+	assert.False(t, ok)
+
+	response = serveTestSessionRequest(router, http.MethodPost, "/login", sessionId)
+	rotatedId := string(response.Body())
+	assert.NotEqual(t, sessionId, rotatedId)
+	cookie, _ = getTestResponseCookie(response, DefaultSessionCookieName)
+	assert.Equal(t, rotatedId, string(cookie.Value()))
+	assert.Equal(t, 1, store.Len())
+
+	response = serveTestSessionRequest(router, http.MethodGet, "/visits", sessionId)
+	assert.Equal(t, "+", string(response.Body()))
+
+	response = serveTestSessionRequest(router, http.MethodGet, "/visits", rotatedId)
+	assert.Equal(t, "+++", string(response.Body()))
+
+	response = serveTestSessionRequest(router, http.MethodPost, "/logout", rotatedId)
+	cookie, _ = getTestResponseCookie(response, DefaultSessionCookieName)
+	assert.Empty(t, cookie.Value())
+	data, _ := store.Load(rotatedId)
+	assert.Nil(t, data)
+}
+
+func TestSessionInterceptor_Timeouts(t *testing.T) {
+	now := time.Now()
+	interceptor := NewSessionInterceptor(nil).
+		WithIdleTimeout(10 * time.Minute).
+		WithAbsoluteTimeout(time.Hour)
+	interceptor.clock = func() time.Time {
+		return now
+	}
+	router := NewRouter(WithControllers(testSessionController{}), WithInterceptors(interceptor))
+
+	response := serveTestSessionRequest(router, http.MethodGet, "/visits", "")
+	cookie, _ := getTestResponseCookie(response, DefaultSessionCookieName)
+	sessionId := string(cookie.Value())
+
+	for i := 0; i < 6; i++ {
+		now = now.Add(9 * time.Minute)
+		response = serveTestSessionRequest(router, http.MethodGet, "/visits", sessionId)
+		assert.Equal(t, strings.Repeat("+", i+2), string(response.Body()))
+	}
+
+	now = now.Add(9 * time.Minute)
+	response = serveTestSessionRequest(router, http.MethodGet, "/visits", sessionId)
+	assert.Equal(t, "+", string(response.Body()))
+
+	cookie, _ = getTestResponseCookie(response, DefaultSessionCookieName)
+	sessionId = string(cookie.Value())
+	now = now.Add(11 * time.Minute)
+	response = serveTestSessionRequest(router, http.MethodGet, "/visits", sessionId)
+	assert.Equal(t, "+", string(response.Body()))
+}
+
+func TestSessionInterceptor_CookieStore(t *testing.T) {
+	interceptor := NewSessionInterceptor(&SessionProperties{
+		Enabled:        true,
+		Store:          SessionStoreCookie,
+		Secret:         "new-secret, old-secret",
+		CookieSecure:   true,
+		CookieSameSite: "strict",
+	})
+	router := NewRouter(WithControllers(testSessionController{}), WithInterceptors(interceptor))
+
+	response := serveTestSessionRequest(router, http.MethodGet, "/visits", "")
+	cookie, _ := getTestResponseCookie(response, DefaultSessionCookieName)
+	assert.True(t, cookie.Secure())
+	assert.False(t, cookie.HTTPOnly())
+	assert.Equal(t, fasthttp.CookieSameSiteStrictMode, cookie.SameSite())
+	assert.NotContains(t, string(cookie.Value()), "visits")
+
+	response = serveTestSessionRequest(router, http.MethodGet, "/visits", string(cookie.Value()))
+	assert.Equal(t, "++", string(response.Body()))
+
+	oldStore := NewCookieSessionStore("old-secret")
+	value, err := oldStore.Save(&SessionData{Id: "id", Attributes: map[string]interface{}{"visits": "+++"}, CreatedAt: time.Now(), LastAccessedAt: time.Now()}, time.Hour)
+	assert.Nil(t, err)
+	response = serveTestSessionRequest(router, http.MethodGet, "/visits", value)
+	assert.Equal(t, "++++", string(response.Body()))
+
+	response = serveTestSessionRequest(router, http.MethodGet, "/visits", value[:len(value)-2]+"AA")
+	assert.Equal(t, "+", string(response.Body()))
+
+	_, err = NewCookieSessionStore("other-secret").Load(value)
+	assert.Equal(t, ErrInvalidSession, err)
+
+	_, err = oldStore.Save(&SessionData{Attributes: map[string]interface{}{"data": strings.Repeat("x", 4096)}}, time.Hour)
+	assert.Equal(t, ErrSessionTooLarge, err)
+}
+
+func TestSessionInterceptor_Configuration(t *testing.T) {
+	assert.Nil(t, NewWebRequestContext(RequestMethodGet, "/visits").Session())
+
+	assert.Panics(t, func() {
+		NewSessionInterceptor(&SessionProperties{Enabled: true, Store: SessionStoreCookie})
+	})
+
+	assert.Panics(t, func() {
+		NewSessionInterceptor(&SessionProperties{Enabled: true, Store: "redis"})
+	})
+}
@@ -0,0 +1,83 @@
+package web
+
+import (
+	"github.com/procyon-projects/procyon-context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+const serverShutdownListenerName = "github.com.procyon.web.ServerShutdownListener"
+
+type serverShutdownListener struct {
+	ctx *ProcyonServerApplicationContext
+}
+
+func (listener serverShutdownListener) GetApplicationListenerName() string {
+	return serverShutdownListenerName
+}
+
+func (listener serverShutdownListener) SubscribeEvents() []context.ApplicationEventId {
+	return []context.ApplicationEventId{
+		context.ApplicationContextClosedEventId(),
+	}
+}
+
+func (listener serverShutdownListener) OnApplicationEvent(ctx context.Context, event context.ApplicationEvent) {
+	listener.ctx.shutdown()
+}
+
+func (ctx *ProcyonServerApplicationContext) Stop() error {
+	ctx.stopOnce.Do(func() {
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for index, server := range []Server{ctx.server, ctx.managementServer} {
+			if server == nil {
+				continue
+			}
+
+			wg.Add(1)
+			go func(index int, server Server) {
+				defer wg.Done()
+				errs[index] = server.Stop()
+			}(index, server)
+		}
+		wg.Wait()
+
+		if errs[0] != nil {
+			ctx.stopErr = errs[0]
+		} else {
+			ctx.stopErr = errs[1]
+		}
+	})
+	return ctx.stopErr
+}
+
+func (ctx *ProcyonServerApplicationContext) shutdown() {
+	if err := ctx.Stop(); err != nil {
+		ctx.GetLogger().Error(ctx, "Server shutdown failed : "+err.Error())
+	}
+}
+
+func (ctx *ProcyonServerApplicationContext) handleShutdownSignals() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		sig := ctx.waitForShutdownSignal(signals)
+		signal.Stop(signals)
+
+		process, err := os.FindProcess(os.Getpid())
+		if err != nil || process.Signal(sig) != nil {
+			os.Exit(1)
+		}
+	}()
+}
+
+func (ctx *ProcyonServerApplicationContext) waitForShutdownSignal(signals <-chan os.Signal) os.Signal {
+	sig := <-signals
+	ctx.GetLogger().Info(ctx, "Received "+sig.String()+", shutting down")
+	ctx.shutdown()
+	return sig
+}
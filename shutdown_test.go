@@ -0,0 +1,59 @@
+package web
+
+import (
+	"github.com/procyon-projects/procyon-context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"syscall"
+	"testing"
+)
+
+type testStoppableServer struct {
+	ProcyonWebServer
+	stopCount int
+}
+
+func (server *testStoppableServer) Stop() error {
+	server.stopCount++
+	return nil
+}
+
+func newTestShutdownContext() (*ProcyonServerApplicationContext, *testStoppableServer) {
+	ctx := NewProcyonServerApplicationContext("test-app", "test-context")
+	ctx.SetLogger(context.NewSimpleLogger())
+
+	server := &testStoppableServer{}
+	ctx.server = server
+	return ctx, server
+}
+
+func TestServerShutdownListener_StopsServerWhenContextIsClosed(t *testing.T) {
+	ctx, server := newTestShutdownContext()
+	listener := serverShutdownListener{ctx}
+
+	assert.Equal(t, []context.ApplicationEventId{context.ApplicationContextClosedEventId()}, listener.SubscribeEvents())
+	listener.OnApplicationEvent(ctx, context.NewApplicationContextClosedEvent(ctx))
+	assert.Equal(t, 1, server.stopCount)
+}
+
+func TestProcyonServerApplicationContext_StopsServerOnSignal(t *testing.T) {
+	ctx, server := newTestShutdownContext()
+
+	signals := make(chan os.Signal, 1)
+	signals <- syscall.SIGTERM
+	assert.Equal(t, syscall.SIGTERM, ctx.waitForShutdownSignal(signals))
+	assert.Equal(t, 1, server.stopCount)
+
+	serverShutdownListener{ctx}.OnApplicationEvent(ctx, context.NewApplicationContextClosedEvent(ctx))
+	assert.Equal(t, 1, server.stopCount)
+}
+
+func TestProcyonServerApplicationContext_StopsManagementServer(t *testing.T) {
+	ctx, server := newTestShutdownContext()
+	managementServer := &testStoppableServer{}
+	ctx.managementServer = managementServer
+
+	assert.Nil(t, ctx.Stop())
+	assert.Equal(t, 1, server.stopCount)
+	assert.Equal(t, 1, managementServer.stopCount)
+}
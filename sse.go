@@ -0,0 +1,283 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	json "github.com/json-iterator/go"
+	"github.com/valyala/fasthttp"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	MediaTypeTextEventStreamValue = "text/event-stream"
+	HeaderLastEventId             = "Last-Event-ID"
+)
+
+const DefaultEventStreamHeartbeatInterval = 15 * time.Second
+
+var (
+	ErrEventStreamClosed = errors.New("event stream is closed")
+	ErrInvalidEventField = errors.New("event id and event name must not contain line breaks")
+)
+
+type ServerSentEvent struct {
+	Id    string
+	Event string
+	Data  interface{}
+	Retry time.Duration
+}
+
+type EventStreamHandler func(stream *EventStream)
+
+type EventStreamOption func(stream *EventStream)
+
+func EventStreamHeartbeat(interval time.Duration) EventStreamOption {
+	return func(stream *EventStream) {
+		stream.heartbeatInterval = interval
+	}
+}
+
+func EventStreamRetry(retry time.Duration) EventStreamOption {
+	return func(stream *EventStream) {
+		stream.retry = retry
+	}
+}
+
+type EventStream struct {
+	contextId         string
+	lastEventId       string
+	heartbeatInterval time.Duration
+	retry             time.Duration
+	writer            *bufio.Writer
+	mu                sync.Mutex
+	closed            bool
+	done              chan struct{}
+	closeOnce         sync.Once
+	heartbeatWait     sync.WaitGroup
+}
+
+func newEventStream(ctx *WebRequestContext, options ...EventStreamOption) *EventStream {
+	stream := &EventStream{
+		contextId:         string([]byte(ctx.contextIdStr)),
+		heartbeatInterval: DefaultEventStreamHeartbeatInterval,
+		done:              make(chan struct{}),
+	}
+
+	if lastEventId, ok := ctx.GetRequestHeader(HeaderLastEventId); ok {
+		stream.lastEventId = lastEventId
+	}
+
+	for _, option := range options {
+		option(stream)
+	}
+	return stream
+}
+
+func (stream *EventStream) GetContextId() string {
+	return stream.contextId
+}
+
+func (stream *EventStream) GetLastEventId() string {
+	return stream.lastEventId
+}
+
+func (stream *EventStream) Done() <-chan struct{} {
+	return stream.done
+}
+
+func (stream *EventStream) IsClosed() bool {
+	select {
+	case <-stream.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (stream *EventStream) Send(event ServerSentEvent) error {
+	if strings.ContainsAny(event.Id, "\r\n") || strings.ContainsAny(event.Event, "\r\n") {
+		return ErrInvalidEventField
+	}
+
+	var buffer bytes.Buffer
+
+	if event.Id != "" {
+		buffer.WriteString("id: ")
+		buffer.WriteString(event.Id)
+		buffer.WriteByte('\n')
+	}
+
+	if event.Event != "" {
+		buffer.WriteString("event: ")
+		buffer.WriteString(event.Event)
+		buffer.WriteByte('\n')
+	}
+
+	if event.Retry > 0 {
+		buffer.WriteString("retry: ")
+		buffer.WriteString(strconv.FormatInt(event.Retry.Milliseconds(), 10))
+		buffer.WriteByte('\n')
+	}
+
+	data, err := stream.encodeData(event.Data)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range splitEventLines(data) {
+		buffer.WriteString("data: ")
+		buffer.Write(line)
+		buffer.WriteByte('\n')
+	}
+
+	buffer.WriteByte('\n')
+	return stream.write(buffer.Bytes())
+}
+
+func (stream *EventStream) SendData(data interface{}) error {
+	return stream.Send(ServerSentEvent{Data: data})
+}
+
+func (stream *EventStream) SendComment(comment string) error {
+	var buffer bytes.Buffer
+	for _, line := range splitEventLines([]byte(comment)) {
+		buffer.WriteByte(':')
+		if len(line) != 0 {
+			buffer.WriteByte(' ')
+			buffer.Write(line)
+		}
+		buffer.WriteByte('\n')
+	}
+	buffer.WriteByte('\n')
+	return stream.write(buffer.Bytes())
+}
+
+func splitEventLines(data []byte) [][]byte {
+	data = bytes.ReplaceAll(data, []byte{'\r', '\n'}, []byte{'\n'})
+	data = bytes.ReplaceAll(data, []byte{'\r'}, []byte{'\n'})
+	return bytes.Split(data, []byte{'\n'})
+}
+
+func (stream *EventStream) encodeData(data interface{}) ([]byte, error) {
+	switch value := data.(type) {
+	case nil:
+		return []byte{}, nil
+	case string:
+		return []byte(value), nil
+	case []byte:
+		return value, nil
+	default:
+		return json.Marshal(value)
+	}
+}
+
+func (stream *EventStream) write(data []byte) error {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if stream.closed {
+		return ErrEventStreamClosed
+	}
+
+	_, err := stream.writer.Write(data)
+	if err == nil {
+		err = stream.writer.Flush()
+	}
+
+	if err != nil {
+		stream.closeLocked()
+		return err
+	}
+	return nil
+}
+
+func (stream *EventStream) close() {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	stream.closeLocked()
+}
+
+func (stream *EventStream) closeLocked() {
+	stream.closed = true
+	stream.closeOnce.Do(func() {
+		close(stream.done)
+	})
+}
+
+func (stream *EventStream) heartbeat(stop <-chan struct{}) {
+	ticker := time.NewTicker(stream.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if stream.SendComment("") != nil {
+				return
+			}
+		case <-stop:
+			return
+		case <-stream.done:
+			return
+		}
+	}
+}
+
+func (stream *EventStream) run(writer *bufio.Writer, handler EventStreamHandler) {
+	stream.writer = writer
+
+	if stream.retry > 0 {
+		retry := "retry: " + strconv.FormatInt(stream.retry.Milliseconds(), 10) + "\n\n"
+		if stream.write([]byte(retry)) != nil {
+			return
+		}
+	} else if stream.write([]byte(":\n\n")) != nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	if stream.heartbeatInterval > 0 {
+		stream.heartbeatWait.Add(1)
+		go func() {
+			defer stream.heartbeatWait.Done()
+			stream.heartbeat(stop)
+		}()
+	}
+
+	defer func() {
+		close(stop)
+		stream.heartbeatWait.Wait()
+		stream.close()
+	}()
+
+	handler(stream)
+}
+
+func (ctx *WebRequestContext) GetLastEventId() string {
+	lastEventId, _ := ctx.GetRequestHeader(HeaderLastEventId)
+	return lastEventId
+}
+
+func (ctx *WebRequestContext) StartEventStream(handler EventStreamHandler, options ...EventStreamOption) {
+	if handler == nil {
+		panic("Event stream handler must not be null")
+	}
+
+	stream := newEventStream(ctx, options...)
+
+	ctx.responseEntity.status = http.StatusOK
+	ctx.responseWritten = true
+
+	ctx.fastHttpRequestContext.SetStatusCode(http.StatusOK)
+	ctx.fastHttpRequestContext.SetContentType(MediaTypeTextEventStreamValue)
+	ctx.fastHttpRequestContext.Response.Header.Set(fasthttp.HeaderCacheControl, "no-cache")
+	ctx.fastHttpRequestContext.Response.Header.Set(fasthttp.HeaderConnection, "keep-alive")
+	ctx.fastHttpRequestContext.Response.Header.Set("X-Accel-Buffering", "no")
+	ctx.fastHttpRequestContext.SetBodyStreamWriter(func(writer *bufio.Writer) {
+		stream.run(writer, handler)
+	})
+}
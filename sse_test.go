@@ -0,0 +1,97 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type errorWriter struct{}
+
+func (writer errorWriter) Write(p []byte) (int, error) {
+	return 0, ErrEventStreamClosed
+}
+
+func TestWebRequestContext_StartEventStream(t *testing.T) {
+	ctx := &WebRequestContext{
+		router: &ProcyonRouter{
+			responseBodyWriter: newDefaultResponseBodyWriter(),
+		},
+	}
+	ctx.fastHttpRequestContext = &fasthttp.RequestCtx{}
+	ctx.fastHttpRequestContext.Request.Header.Set(HeaderLastEventId, "41")
+
+	var lastEventId string
+	ctx.StartEventStream(func(stream *EventStream) {
+		lastEventId = stream.GetLastEventId()
+		assert.Nil(t, stream.Send(ServerSentEvent{Id: "42", Event: "status", Data: "line1\nline2"}))
+		assert.Nil(t, stream.SendData(map[string]string{"status": "UP"}))
+		assert.Nil(t, stream.SendComment("keep-alive"))
+	}, EventStreamHeartbeat(0), EventStreamRetry(3*time.Second))
+
+	ctx.writeResponse()
+
+	assert.Equal(t, "41", ctx.GetLastEventId())
+	assert.Equal(t, http.StatusOK, ctx.fastHttpRequestContext.Response.StatusCode())
+	assert.Equal(t, MediaTypeTextEventStreamValue, string(ctx.fastHttpRequestContext.Response.Header.ContentType()))
+	assert.Equal(t, "no-cache", string(ctx.fastHttpRequestContext.Response.Header.Peek(fasthttp.HeaderCacheControl)))
+
+	body := string(ctx.fastHttpRequestContext.Response.Body())
+	assert.Equal(t, "41", lastEventId)
+	assert.Equal(t, "retry: 3000\n\n"+
+		"id: 42\nevent: status\ndata: line1\ndata: line2\n\n"+
+		"data: {\"status\":\"UP\"}\n\n"+
+		": keep-alive\n\n", body)
+}
+
+func TestEventStream_Heartbeat(t *testing.T) {
+	var buffer bytes.Buffer
+	stream := &EventStream{
+		heartbeatInterval: 10 * time.Millisecond,
+		done:              make(chan struct{}),
+	}
+
+	stream.run(bufio.NewWriter(&buffer), func(stream *EventStream) {
+		time.Sleep(35 * time.Millisecond)
+	})
+
+	assert.True(t, stream.IsClosed())
+	assert.Contains(t, buffer.String(), ":\n\n:\n\n")
+}
+
+func TestEventStream_Disconnect(t *testing.T) {
+	stream := &EventStream{
+		done: make(chan struct{}),
+	}
+	stream.writer = bufio.NewWriterSize(errorWriter{}, 16)
+
+	assert.NotNil(t, stream.SendData("test"))
+
+	select {
+	case <-stream.Done():
+	default:
+		t.Error("stream must be closed after the client disconnects")
+	}
+
+	assert.Equal(t, ErrEventStreamClosed, stream.SendData("test"))
+}
+
+func TestEventStream_RejectsLineBreaksInFields(t *testing.T) {
+	var buffer bytes.Buffer
+	stream := &EventStream{
+		done: make(chan struct{}),
+	}
+	stream.writer = bufio.NewWriter(&buffer)
+
+	assert.Equal(t, ErrInvalidEventField, stream.Send(ServerSentEvent{Id: "1\ndata: injected"}))
+	assert.Equal(t, ErrInvalidEventField, stream.Send(ServerSentEvent{Event: "status\revent: injected"}))
+	assert.Empty(t, buffer.String())
+
+	assert.Nil(t, stream.Send(ServerSentEvent{Data: "line1\rid: 2\r\nline3"}))
+	assert.Nil(t, stream.SendComment("comment\rdata: injected"))
+	assert.Equal(t, "data: line1\ndata: id: 2\ndata: line3\n\n: comment\n: data: injected\n\n", buffer.String())
+}
@@ -0,0 +1,421 @@
+package web
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	json "github.com/json-iterator/go"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	HeaderTraceparent = "traceparent"
+	HeaderTracestate  = "tracestate"
+)
+
+const (
+	traceIdLength       = 32
+	spanIdLength        = 16
+	traceparentLength   = 55
+	maxTracestateLength = 512
+	traceFlagSampled    = 0x01
+)
+
+type TraceContext struct {
+	TraceId      string
+	SpanId       string
+	ParentSpanId string
+	Flags        byte
+	TraceState   string
+}
+
+func ParseTraceparent(value string) (TraceContext, bool) {
+	if len(value) < traceparentLength {
+		return TraceContext{}, false
+	}
+
+	version := value[0:2]
+	if !isLowerHex(version) || version == "ff" {
+		return TraceContext{}, false
+	}
+
+	if version == "00" && len(value) != traceparentLength {
+		return TraceContext{}, false
+	}
+
+	if len(value) > traceparentLength && value[traceparentLength] != '-' {
+		return TraceContext{}, false
+	}
+
+	if value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return TraceContext{}, false
+	}
+
+	traceId := value[3:35]
+	parentId := value[36:52]
+	flags := value[53:55]
+	if !isLowerHex(traceId) || isZeroId(traceId) ||
+		!isLowerHex(parentId) || isZeroId(parentId) || !isLowerHex(flags) {
+		return TraceContext{}, false
+	}
+
+	flagValue, _ := strconv.ParseUint(flags, 16, 8)
+	return TraceContext{
+		TraceId:      traceId,
+		ParentSpanId: parentId,
+		Flags:        byte(flagValue),
+	}, true
+}
+
+func isLowerHex(value string) bool {
+	for index := 0; index < len(value); index++ {
+		character := value[index]
+		if (character < '0' || character > '9') && (character < 'a' || character > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isZeroId(value string) bool {
+	for index := 0; index < len(value); index++ {
+		if value[index] != '0' {
+			return false
+		}
+	}
+	return true
+}
+
+func (traceContext TraceContext) IsValid() bool {
+	return traceContext.TraceId != "" && traceContext.SpanId != ""
+}
+
+func (traceContext TraceContext) IsSampled() bool {
+	return traceContext.Flags&traceFlagSampled != 0
+}
+
+func (traceContext TraceContext) GetTraceparent() string {
+	if !traceContext.IsValid() {
+		return ""
+	}
+
+	flags := hex.EncodeToString([]byte{traceContext.Flags})
+	return "00-" + traceContext.TraceId + "-" + traceContext.SpanId + "-" + flags
+}
+
+func (traceContext TraceContext) InjectHeader(header http.Header) {
+	traceparent := traceContext.GetTraceparent()
+	if traceparent == "" {
+		return
+	}
+
+	header.Set(HeaderTraceparent, traceparent)
+	if traceContext.TraceState != "" {
+		header.Set(HeaderTracestate, traceContext.TraceState)
+	}
+}
+
+func newTraceId() string {
+	return randomHex(traceIdLength / 2)
+}
+
+func newSpanId() string {
+	return randomHex(spanIdLength / 2)
+}
+
+func randomHex(size int) string {
+	buffer := make([]byte, size)
+	for {
+		if _, err := rand.Read(buffer); err != nil {
+			panic(err)
+		}
+
+		encoded := hex.EncodeToString(buffer)
+		if !isZeroId(encoded) {
+			return encoded
+		}
+	}
+}
+
+type SpanKind string
+
+const (
+	SpanKindServer   SpanKind = "server"
+	SpanKindInternal SpanKind = "internal"
+)
+
+type Span struct {
+	TraceId      string            `json:"traceId"`
+	SpanId       string            `json:"spanId"`
+	ParentSpanId string            `json:"parentSpanId,omitempty"`
+	Name         string            `json:"name"`
+	Kind         SpanKind          `json:"kind"`
+	StartTime    time.Time         `json:"startTime"`
+	EndTime      time.Time         `json:"endTime"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+func (span Span) GetDuration() time.Duration {
+	return span.EndTime.Sub(span.StartTime)
+}
+
+type SpanExporter interface {
+	ExportSpans(spans []Span)
+}
+
+const (
+	SpanExporterNone   = "none"
+	SpanExporterStdout = "stdout"
+	SpanExporterMemory = "memory"
+)
+
+func newSpanExporter(name string) SpanExporter {
+	switch name {
+	case SpanExporterStdout:
+		return NewStdoutSpanExporter()
+	case SpanExporterMemory:
+		return NewInMemorySpanExporter()
+	default:
+		return nil
+	}
+}
+
+type InMemorySpanExporter struct {
+	spans []Span
+	mu    sync.Mutex
+}
+
+func NewInMemorySpanExporter() *InMemorySpanExporter {
+	return &InMemorySpanExporter{
+		spans: make([]Span, 0),
+	}
+}
+
+func (exporter *InMemorySpanExporter) ExportSpans(spans []Span) {
+	exporter.mu.Lock()
+	exporter.spans = append(exporter.spans, spans...)
+	exporter.mu.Unlock()
+}
+
+func (exporter *InMemorySpanExporter) GetSpans() []Span {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+
+	spans := make([]Span, len(exporter.spans))
+	copy(spans, exporter.spans)
+	return spans
+}
+
+func (exporter *InMemorySpanExporter) Reset() {
+	exporter.mu.Lock()
+	exporter.spans = exporter.spans[:0]
+	exporter.mu.Unlock()
+}
+
+type StdoutSpanExporter struct {
+	writer io.Writer
+	mu     sync.Mutex
+}
+
+func NewStdoutSpanExporter() *StdoutSpanExporter {
+	return &StdoutSpanExporter{
+		writer: os.Stdout,
+	}
+}
+
+func (exporter *StdoutSpanExporter) ExportSpans(spans []Span) {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+
+	for _, span := range spans {
+		encodedSpan, err := json.Marshal(span)
+		if err != nil {
+			continue
+		}
+		_, _ = exporter.writer.Write(append(encodedSpan, '\n'))
+	}
+}
+
+type tracePhase int
+
+const (
+	tracePhaseNone tracePhase = iota
+	tracePhaseBeforeInterceptors
+	tracePhaseHandler
+	tracePhaseAfterInterceptors
+	tracePhaseAfterCompletionInterceptors
+	tracePhaseErrorHandling
+)
+
+var tracePhaseNames = map[tracePhase]string{
+	tracePhaseBeforeInterceptors:          "interceptors.before",
+	tracePhaseHandler:                     "handler",
+	tracePhaseAfterInterceptors:           "interceptors.after",
+	tracePhaseAfterCompletionInterceptors: "interceptors.afterCompletion",
+	tracePhaseErrorHandling:               "error",
+}
+
+type requestTrace struct {
+	serverSpan Span
+	phase      tracePhase
+	phaseSpan  Span
+	spans      []Span
+}
+
+func (trace *requestTrace) enterPhase(phase tracePhase) {
+	if trace.phase == phase {
+		return
+	}
+
+	now := time.Now()
+	trace.endPhase(now)
+
+	trace.phase = phase
+	trace.phaseSpan = Span{
+		TraceId:      trace.serverSpan.TraceId,
+		SpanId:       newSpanId(),
+		ParentSpanId: trace.serverSpan.SpanId,
+		Name:         tracePhaseNames[phase],
+		Kind:         SpanKindInternal,
+		StartTime:    now,
+	}
+}
+
+func (trace *requestTrace) endPhase(now time.Time) {
+	if trace.phase == tracePhaseNone {
+		return
+	}
+
+	trace.phaseSpan.EndTime = now
+	trace.spans = append(trace.spans, trace.phaseSpan)
+	trace.phase = tracePhaseNone
+}
+
+func (trace *requestTrace) failPhase(err error) {
+	if trace.phase != tracePhaseNone && err != nil && trace.phaseSpan.Error == "" {
+		trace.phaseSpan.Error = err.Error()
+	}
+}
+
+func (ctx *WebRequestContext) prepareTrace() {
+	if ctx.fastHttpRequestContext == nil {
+		return
+	}
+
+	requestHeader := &ctx.fastHttpRequestContext.Request.Header
+	traceparent := requestHeader.Peek(HeaderTraceparent)
+	if len(traceparent) == 0 {
+		return
+	}
+
+	traceContext, ok := ParseTraceparent(string(traceparent))
+	if !ok {
+		return
+	}
+
+	tracestate := requestHeader.Peek(HeaderTracestate)
+	if len(tracestate) != 0 && len(tracestate) <= maxTracestateLength {
+		traceContext.TraceState = string(tracestate)
+	}
+	ctx.traceContext = traceContext
+}
+
+func (ctx *WebRequestContext) GetTraceContext() TraceContext {
+	if ctx.traceContext.TraceId == "" {
+		ctx.traceContext.TraceId = newTraceId()
+		ctx.traceContext.Flags = traceFlagSampled
+	}
+
+	if ctx.traceContext.SpanId == "" {
+		ctx.traceContext.SpanId = newSpanId()
+	}
+	return ctx.traceContext
+}
+
+func (ctx *WebRequestContext) startTrace() {
+	if ctx.router == nil || ctx.router.spanExporter == nil || ctx.fastHttpRequestContext == nil {
+		return
+	}
+
+	traceContext := ctx.GetTraceContext()
+	if !traceContext.IsSampled() {
+		return
+	}
+
+	ctx.trace = &requestTrace{
+		serverSpan: Span{
+			TraceId:      traceContext.TraceId,
+			SpanId:       traceContext.SpanId,
+			ParentSpanId: traceContext.ParentSpanId,
+			Name:         string(ctx.fastHttpRequestContext.Method()),
+			Kind:         SpanKindServer,
+			StartTime:    time.Now(),
+		},
+		spans: make([]Span, 0, 4),
+	}
+}
+
+func (ctx *WebRequestContext) traceHandler(index int) {
+	chain := ctx.handlerChain
+	switch {
+	case index < chain.handlerIndex:
+		ctx.trace.enterPhase(tracePhaseBeforeInterceptors)
+	case index == chain.handlerIndex:
+		ctx.trace.enterPhase(tracePhaseHandler)
+	case index < chain.afterCompletionStartIndex:
+		ctx.trace.enterPhase(tracePhaseAfterInterceptors)
+	default:
+		ctx.trace.enterPhase(tracePhaseAfterCompletionInterceptors)
+	}
+}
+
+func (ctx *WebRequestContext) traceError(err error) {
+	if ctx.trace == nil {
+		return
+	}
+
+	ctx.trace.failPhase(err)
+	ctx.trace.enterPhase(tracePhaseErrorHandling)
+	ctx.trace.phaseSpan.Error = err.Error()
+}
+
+func (ctx *WebRequestContext) finishTrace() {
+	if ctx.trace == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx.trace.endPhase(now)
+
+	serverSpan := ctx.trace.serverSpan
+	serverSpan.EndTime = now
+
+	statusCode := ctx.fastHttpRequestContext.Response.StatusCode()
+	serverSpan.Attributes = map[string]string{
+		"http.method":      string(ctx.fastHttpRequestContext.Method()),
+		"http.target":      string(ctx.fastHttpRequestContext.Path()),
+		"http.status_code": strconv.Itoa(statusCode),
+		"context.id":       ctx.contextIdStr,
+	}
+
+	if ctx.handlerChain != nil && ctx.handlerChain.GetPattern() != "" {
+		serverSpan.Name += " " + ctx.handlerChain.GetPattern()
+		serverSpan.Attributes["http.route"] = ctx.handlerChain.GetPattern()
+	}
+
+	if ctx.internalError != nil {
+		serverSpan.Error = ctx.internalError.Error()
+	} else if ctx.httpError != nil && ctx.httpError.Code >= http.StatusInternalServerError {
+		serverSpan.Error = ctx.httpError.Error()
+	} else if statusCode >= http.StatusInternalServerError {
+		serverSpan.Error = http.StatusText(statusCode)
+	}
+
+	ctx.router.spanExporter.ExportSpans(append(ctx.trace.spans, serverSpan))
+	ctx.trace = nil
+}
@@ -0,0 +1,141 @@
+package web
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceparent(t *testing.T) {
+	traceContext, ok := ParseTraceparent(testTraceparent)
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceContext.TraceId)
+	assert.Equal(t, "00f067aa0ba902b7", traceContext.ParentSpanId)
+	assert.True(t, traceContext.IsSampled())
+
+	traceContext, ok = ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-future")
+	assert.True(t, ok)
+	assert.False(t, traceContext.IsSampled())
+
+	invalidValues := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01x",
+	}
+	for _, value := range invalidValues {
+		_, ok = ParseTraceparent(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestTraceContext_InjectHeader(t *testing.T) {
+	header := make(http.Header)
+	TraceContext{}.InjectHeader(header)
+	assert.Empty(t, header)
+
+	TraceContext{
+		TraceId:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanId:     "53995c3f42cd8ad8",
+		Flags:      1,
+		TraceState: "vendor=value",
+	}.InjectHeader(header)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-53995c3f42cd8ad8-01", header.Get(HeaderTraceparent))
+	assert.Equal(t, "vendor=value", header.Get(HeaderTracestate))
+}
+
+func TestWebRequestContext_TraceContext(t *testing.T) {
+	ctx := NewWebRequestContext(RequestMethodGet, "/orders",
+		ContextHeader(HeaderTraceparent, testTraceparent),
+		ContextHeader(HeaderTracestate, "vendor=value"),
+	)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", string(ctx.GetContextId()))
+
+	traceContext := ctx.GetTraceContext()
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceContext.TraceId)
+	assert.Equal(t, "00f067aa0ba902b7", traceContext.ParentSpanId)
+	assert.Len(t, traceContext.SpanId, 16)
+	assert.Equal(t, "vendor=value", traceContext.TraceState)
+	assert.Equal(t, traceContext, ctx.GetTraceContext())
+
+	ctx = NewWebRequestContext(RequestMethodGet, "/orders", ContextHeader(HeaderTraceparent, "invalid"))
+	assert.Len(t, string(ctx.GetContextId()), 36)
+
+	traceContext = ctx.GetTraceContext()
+	assert.Len(t, traceContext.TraceId, 32)
+	assert.Empty(t, traceContext.ParentSpanId)
+	assert.True(t, traceContext.IsSampled())
+}
+
+func TestRouter_SpanExporter(t *testing.T) {
+	exporter := NewInMemorySpanExporter()
+	router := NewRouter(
+		WithControllers(testNetHttpController{}, testMetricsController{}),
+		WithInterceptors(testWebSocketAuthInterceptor{}),
+		WithSpanExporter(exporter),
+	)
+
+	serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", map[string]string{
+		HeaderTraceparent: testTraceparent,
+		"X-Token":         "secret",
+	}, "")
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 3)
+	assert.Equal(t, "interceptors.before", spans[0].Name)
+	assert.Equal(t, "handler", spans[1].Name)
+
+	serverSpan := spans[2]
+	assert.Equal(t, "GET /greetings/:name", serverSpan.Name)
+	assert.Equal(t, SpanKindServer, serverSpan.Kind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", serverSpan.TraceId)
+	assert.Equal(t, "00f067aa0ba902b7", serverSpan.ParentSpanId)
+	assert.Equal(t, "200", serverSpan.Attributes["http.status_code"])
+	assert.Equal(t, "/greetings/:name", serverSpan.Attributes["http.route"])
+	assert.Equal(t, serverSpan.SpanId, spans[0].ParentSpanId)
+	assert.Equal(t, serverSpan.SpanId, spans[1].ParentSpanId)
+	assert.Empty(t, serverSpan.Error)
+
+	exporter.Reset()
+	serveTestAdapterRequest(router, http.MethodGet, "/fail", map[string]string{"X-Token": "secret"}, "")
+
+	spans = exporter.GetSpans()
+	assert.Len(t, spans, 4)
+	assert.Equal(t, "handler", spans[1].Name)
+	assert.Equal(t, "unexpected failure", spans[1].Error)
+	assert.Equal(t, "error", spans[2].Name)
+	assert.Equal(t, "unexpected failure", spans[3].Error)
+	assert.Equal(t, "500", spans[3].Attributes["http.status_code"])
+	assert.Len(t, spans[3].TraceId, 32)
+
+	exporter.Reset()
+	serveTestAdapterRequest(router, http.MethodGet, "/greetings/procyon", map[string]string{
+		HeaderTraceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+	}, "")
+	assert.Empty(t, exporter.GetSpans())
+}
+
+func TestStdoutSpanExporter(t *testing.T) {
+	var buffer bytes.Buffer
+	exporter := NewStdoutSpanExporter()
+	exporter.writer = &buffer
+
+	exporter.ExportSpans([]Span{
+		{TraceId: "4bf92f3577b34da6a3ce929d0e0e4736", SpanId: "53995c3f42cd8ad8", Name: "handler", Kind: SpanKindInternal},
+		{TraceId: "4bf92f3577b34da6a3ce929d0e0e4736", SpanId: "00f067aa0ba902b7", Name: "GET /orders", Kind: SpanKindServer},
+	})
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "\"name\":\"handler\"")
+	assert.Contains(t, lines[1], "\"kind\":\"server\"")
+}
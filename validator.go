@@ -1,21 +1,188 @@
 package web
 
-import "github.com/go-playground/validator"
+import (
+	"errors"
+	"gopkg.in/go-playground/validator.v9"
+	"reflect"
+	"strings"
+)
 
 type Validator interface {
 	Validate(val interface{}) error
 }
 
+const defaultValidationMessage = "{0} failed on the '{1}' rule"
+
+const requestSectionTagPrefix = "@"
+
+var validationMessages = map[string]string{
+	"required": "{0} is required",
+	"min":      "{0} must be at least {1}",
+	"max":      "{0} must be at most {1}",
+	"len":      "{0} must have a length of {1}",
+	"eq":       "{0} must be equal to {1}",
+	"ne":       "{0} must not be equal to {1}",
+	"gt":       "{0} must be greater than {1}",
+	"gte":      "{0} must be greater than or equal to {1}",
+	"lt":       "{0} must be less than {1}",
+	"lte":      "{0} must be less than or equal to {1}",
+	"oneof":    "{0} must be one of [{1}]",
+	"email":    "{0} must be a valid email address",
+	"url":      "{0} must be a valid URL",
+	"uuid":     "{0} must be a valid UUID",
+	"alpha":    "{0} must contain only letters",
+	"alphanum": "{0} must contain only letters and numbers",
+	"numeric":  "{0} must be a valid number",
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+type ValidationError struct {
+	Errors []FieldError
+	cause  error
+}
+
+func NewValidationError(validationErrors validator.ValidationErrors) *ValidationError {
+	validationError := &ValidationError{
+		Errors: make([]FieldError, 0, len(validationErrors)),
+		cause:  validationErrors,
+	}
+
+	for _, fieldError := range validationErrors {
+		field := fieldPath(fieldError.Namespace())
+		validationError.Errors = append(validationError.Errors, FieldError{
+			Field:   field,
+			Rule:    fieldError.Tag(),
+			Param:   fieldError.Param(),
+			Message: validationMessage(field, fieldError.Tag(), fieldError.Param()),
+		})
+	}
+
+	return validationError
+}
+
+func (err *ValidationError) Error() string {
+	messages := make([]string, 0, len(err.Errors))
+	for _, fieldError := range err.Errors {
+		messages = append(messages, fieldError.Message)
+	}
+	return "validation failed : " + strings.Join(messages, ", ")
+}
+
+func (err *ValidationError) Unwrap() error {
+	return err.cause
+}
+
+func (err *ValidationError) localize(ctx *WebRequestContext) *ValidationError {
+	validationError := &ValidationError{
+		Errors: make([]FieldError, 0, len(err.Errors)),
+		cause:  err.cause,
+	}
+
+	var source *TranslatorMessageSource
+	if ctx.router != nil {
+		source, _ = ctx.router.messageSource.(*TranslatorMessageSource)
+	}
+	validationErrors, _ := err.cause.(validator.ValidationErrors)
+
+	for index, fieldError := range err.Errors {
+		message, ok := ctx.GetMessage(validationMessageCodePrefix+fieldError.Rule, fieldError.Field, fieldError.Param)
+		if !ok && source != nil && len(validationErrors) == len(err.Errors) {
+			message, ok = source.translateFieldError(ctx.GetLocale(), validationErrors[index])
+		}
+
+		if !ok {
+			message, ok = ctx.GetMessage(validationDefaultMessageCode, fieldError.Field, fieldError.Rule)
+		}
+
+		if ok {
+			fieldError.Message = message
+		}
+		validationError.Errors = append(validationError.Errors, fieldError)
+	}
+
+	return validationError
+}
+
+func (err *ValidationError) toProblemErrors() []ProblemError {
+	problemErrors := make([]ProblemError, 0, len(err.Errors))
+	for _, fieldError := range err.Errors {
+		problemErrors = append(problemErrors, ProblemError{
+			Field:  fieldError.Field,
+			Rule:   fieldError.Rule,
+			Param:  fieldError.Param,
+			Detail: fieldError.Message,
+		})
+	}
+	return problemErrors
+}
+
+func fieldPath(namespace string) string {
+	segments := strings.Split(namespace, ".")
+	if len(segments) == 1 {
+		return namespace
+	}
+
+	segments = segments[1:]
+	if len(segments) > 1 && strings.HasPrefix(segments[0], requestSectionTagPrefix) {
+		segments = segments[1:]
+	}
+	return strings.Join(segments, ".")
+}
+
+func validationMessage(field string, rule string, param string) string {
+	if message, ok := validationMessages[rule]; ok {
+		return strings.NewReplacer("{0}", field, "{1}", param).Replace(message)
+	}
+	return strings.NewReplacer("{0}", field, "{1}", rule).Replace(defaultValidationMessage)
+}
+
 type defaultValidator struct {
 	validate *validator.Validate
 }
 
-func newDefaultValidator() defaultValidator {
+func newValidate() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(jsonTagName)
+	return validate
+}
+
+func newDefaultValidator(messageSource MessageSource) defaultValidator {
+	if source, ok := messageSource.(*TranslatorMessageSource); ok {
+		return defaultValidator{
+			validate: source.getValidate(),
+		}
+	}
+
 	return defaultValidator{
-		validate: validator.New(),
+		validate: newValidate(),
 	}
 }
 
 func (v defaultValidator) Validate(val interface{}) error {
-	return v.validate.Struct(val)
+	err := v.validate.Struct(val)
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return NewValidationError(validationErrors)
+	}
+
+	return err
+}
+
+func jsonTagName(field reflect.StructField) string {
+	if source := field.Tag.Get("request"); source != "" {
+		return requestSectionTagPrefix + source
+	}
+
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	return name
 }
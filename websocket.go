@@ -0,0 +1,632 @@
+package web
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	json "github.com/json-iterator/go"
+	context "github.com/procyon-projects/procyon-context"
+	"github.com/valyala/fasthttp"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	WebSocketContinuationMessage = 0
+	WebSocketTextMessage         = 1
+	WebSocketBinaryMessage       = 2
+	WebSocketCloseMessage        = 8
+	WebSocketPingMessage         = 9
+	WebSocketPongMessage         = 10
+)
+
+const (
+	WebSocketCloseNormalClosure           = 1000
+	WebSocketCloseGoingAway               = 1001
+	WebSocketCloseProtocolError           = 1002
+	WebSocketCloseUnsupportedData         = 1003
+	WebSocketCloseNoStatusReceived        = 1005
+	WebSocketCloseAbnormalClosure         = 1006
+	WebSocketCloseInvalidFramePayloadData = 1007
+	WebSocketClosePolicyViolation         = 1008
+	WebSocketCloseMessageTooBig           = 1009
+	WebSocketCloseInternalServerError     = 1011
+)
+
+const (
+	DefaultWebSocketReadLimit    int64 = 1 << 20
+	DefaultWebSocketWriteTimeout       = 10 * time.Second
+	webSocketVersion                   = "13"
+	webSocketGUID                      = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	webSocketMaxControlPayload         = 125
+)
+
+var (
+	ErrWebSocketClosed       = errors.New("websocket connection is closed")
+	ErrWebSocketReadLimit    = errors.New("websocket message exceeds the read limit")
+	errWebSocketProtocol     = errors.New("websocket protocol error")
+	errWebSocketInvalidFrame = errors.New("websocket frame payload is invalid")
+)
+
+type WebSocketCloseError struct {
+	Code int
+	Text string
+}
+
+func (err *WebSocketCloseError) Error() string {
+	return "websocket: close " + strconv.Itoa(err.Code) + " " + err.Text
+}
+
+type WebSocketHandler func(conn *WebSocketConnection)
+
+type WebSocketOriginChecker func(origin string, ctx *WebRequestContext) bool
+
+type webSocketUpgrader struct {
+	handler        WebSocketHandler
+	allowedOrigins []string
+	checkOrigin    WebSocketOriginChecker
+	subprotocols   []string
+	pingInterval   time.Duration
+	writeTimeout   time.Duration
+	readLimit      int64
+}
+
+func newWebSocketUpgrader(handler WebSocketHandler) *webSocketUpgrader {
+	return &webSocketUpgrader{
+		handler:      handler,
+		writeTimeout: DefaultWebSocketWriteTimeout,
+		readLimit:    DefaultWebSocketReadLimit,
+	}
+}
+
+func WebSocket(handler WebSocketHandler, options ...RequestHandlerOption) RequestHandler {
+	if handler == nil {
+		panic("Handler must not be null")
+	}
+
+	upgrader := newWebSocketUpgrader(handler)
+	upgraderOption := func(requestHandler *RequestHandler) {
+		requestHandler.webSocketUpgrader = upgrader
+	}
+	return newHandler(upgrader.upgrade, RequestMethodGet, append([]RequestHandlerOption{upgraderOption}, options...)...)
+}
+
+func WebSocketOrigins(origins ...string) RequestHandlerOption {
+	return func(handler *RequestHandler) {
+		if handler.webSocketUpgrader != nil {
+			handler.webSocketUpgrader.allowedOrigins = origins
+		}
+	}
+}
+
+func WebSocketCheckOrigin(checker WebSocketOriginChecker) RequestHandlerOption {
+	return func(handler *RequestHandler) {
+		if handler.webSocketUpgrader != nil {
+			handler.webSocketUpgrader.checkOrigin = checker
+		}
+	}
+}
+
+func WebSocketSubprotocols(subprotocols ...string) RequestHandlerOption {
+	return func(handler *RequestHandler) {
+		if handler.webSocketUpgrader != nil {
+			handler.webSocketUpgrader.subprotocols = subprotocols
+		}
+	}
+}
+
+func WebSocketPingInterval(interval time.Duration) RequestHandlerOption {
+	return func(handler *RequestHandler) {
+		if handler.webSocketUpgrader != nil {
+			handler.webSocketUpgrader.pingInterval = interval
+		}
+	}
+}
+
+func WebSocketWriteTimeout(timeout time.Duration) RequestHandlerOption {
+	return func(handler *RequestHandler) {
+		if handler.webSocketUpgrader != nil {
+			handler.webSocketUpgrader.writeTimeout = timeout
+		}
+	}
+}
+
+func WebSocketReadLimit(limit int64) RequestHandlerOption {
+	if limit <= 0 {
+		panic("WebSocket read limit must be greater than zero")
+	}
+
+	return func(handler *RequestHandler) {
+		if handler.webSocketUpgrader != nil {
+			handler.webSocketUpgrader.readLimit = limit
+		}
+	}
+}
+
+func (upgrader *webSocketUpgrader) upgrade(ctx *WebRequestContext) {
+	if ctx.httpError != nil {
+		return
+	}
+
+	requestHeader := &ctx.fastHttpRequestContext.Request.Header
+
+	if !headerContainsToken(requestHeader.Peek(fasthttp.HeaderConnection), "upgrade") ||
+		!headerContainsToken(requestHeader.Peek(fasthttp.HeaderUpgrade), "websocket") {
+		ctx.SetHTTPError(NewHTTPError(http.StatusBadRequest, "websocket: the client is not using the websocket protocol"))
+		return
+	}
+
+	if string(requestHeader.Peek(fasthttp.HeaderSecWebSocketVersion)) != webSocketVersion {
+		ctx.AddResponseHeader(fasthttp.HeaderSecWebSocketVersion, webSocketVersion)
+		ctx.SetHTTPError(NewHTTPError(http.StatusUpgradeRequired, "websocket: unsupported version"))
+		return
+	}
+
+	key := string(requestHeader.Peek(fasthttp.HeaderSecWebSocketKey))
+	if decodedKey, err := base64.StdEncoding.DecodeString(key); err != nil || len(decodedKey) != 16 {
+		ctx.SetHTTPError(NewHTTPError(http.StatusBadRequest, "websocket: invalid Sec-WebSocket-Key header"))
+		return
+	}
+
+	if !upgrader.isOriginAllowed(ctx) {
+		ctx.SetHTTPError(NewHTTPError(http.StatusForbidden, "websocket: origin is not allowed"))
+		return
+	}
+
+	conn := newWebSocketConnection(ctx, upgrader)
+	if subprotocol := upgrader.selectSubprotocol(requestHeader.Peek(fasthttp.HeaderSecWebSocketProtocol)); subprotocol != "" {
+		conn.subprotocol = subprotocol
+		ctx.fastHttpRequestContext.Response.Header.Set(fasthttp.HeaderSecWebSocketProtocol, subprotocol)
+	}
+
+	ctx.responseEntity.status = http.StatusSwitchingProtocols
+	ctx.responseWritten = true
+
+	ctx.fastHttpRequestContext.SetStatusCode(http.StatusSwitchingProtocols)
+	ctx.fastHttpRequestContext.Response.Header.Set(fasthttp.HeaderUpgrade, "websocket")
+	ctx.fastHttpRequestContext.Response.Header.Set(fasthttp.HeaderConnection, "Upgrade")
+	ctx.fastHttpRequestContext.Response.Header.Set(fasthttp.HeaderSecWebSocketAccept, computeWebSocketAccept(key))
+	ctx.hijack(func(netConn net.Conn) {
+		conn.serve(netConn, upgrader.handler)
+	})
+}
+
+func (upgrader *webSocketUpgrader) isOriginAllowed(ctx *WebRequestContext) bool {
+	origin, ok := ctx.GetRequestHeader(fasthttp.HeaderOrigin)
+
+	if upgrader.checkOrigin != nil {
+		return upgrader.checkOrigin(origin, ctx)
+	}
+
+	if !ok || origin == "" {
+		return true
+	}
+
+	if len(upgrader.allowedOrigins) != 0 {
+		for _, allowedOrigin := range upgrader.allowedOrigins {
+			if allowedOrigin == "*" || strings.EqualFold(allowedOrigin, origin) {
+				return true
+			}
+		}
+		return false
+	}
+
+	originUrl, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(originUrl.Host, string(ctx.fastHttpRequestContext.Host()))
+}
+
+func (upgrader *webSocketUpgrader) selectSubprotocol(header []byte) string {
+	if len(upgrader.subprotocols) == 0 || len(header) == 0 {
+		return ""
+	}
+
+	for _, requested := range strings.Split(string(header), ",") {
+		requested = strings.TrimSpace(requested)
+		for _, subprotocol := range upgrader.subprotocols {
+			if requested == subprotocol {
+				return subprotocol
+			}
+		}
+	}
+	return ""
+}
+
+func computeWebSocketAccept(key string) string {
+	hash := sha1.New()
+	hash.Write([]byte(key))
+	hash.Write([]byte(webSocketGUID))
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
+}
+
+func headerContainsToken(header []byte, token string) bool {
+	for _, value := range strings.Split(string(header), ",") {
+		if strings.EqualFold(strings.TrimSpace(value), token) {
+			return true
+		}
+	}
+	return false
+}
+
+type WebSocketConnection struct {
+	netConn       net.Conn
+	reader        *bufio.Reader
+	logger        context.Logger
+	contextId     string
+	path          string
+	subprotocol   string
+	pathVariables map[string]string
+	headers       map[string]string
+	values        map[string]interface{}
+	pingInterval  time.Duration
+	writeTimeout  time.Duration
+	readLimit     int64
+	writeMu       sync.Mutex
+	closeSent     bool
+	closed        chan struct{}
+	closeOnce     sync.Once
+}
+
+func newWebSocketConnection(ctx *WebRequestContext, upgrader *webSocketUpgrader) *WebSocketConnection {
+	conn := &WebSocketConnection{
+		contextId:     string([]byte(ctx.contextIdStr)),
+		path:          ctx.GetPath(),
+		pathVariables: make(map[string]string),
+		headers:       make(map[string]string),
+		values:        make(map[string]interface{}),
+		pingInterval:  upgrader.pingInterval,
+		writeTimeout:  upgrader.writeTimeout,
+		readLimit:     upgrader.readLimit,
+		closed:        make(chan struct{}),
+	}
+
+	if ctx.router != nil {
+		conn.logger = ctx.router.logger
+	}
+
+	if ctx.handlerChain != nil {
+		for index, pathVariableName := range ctx.handlerChain.pathVariables {
+			conn.pathVariables[pathVariableName] = ctx.pathVariables[index]
+		}
+	}
+
+	ctx.fastHttpRequestContext.Request.Header.VisitAll(func(key, value []byte) {
+		conn.headers[string(key)] = string(value)
+	})
+
+	for key, value := range ctx.valueMap {
+		conn.values[key] = value
+	}
+	return conn
+}
+
+func (conn *WebSocketConnection) serve(netConn net.Conn, handler WebSocketHandler) {
+	conn.netConn = netConn
+	conn.reader = bufio.NewReader(netConn)
+
+	if conn.pingInterval > 0 {
+		conn.extendReadDeadline()
+		go conn.keepAlive()
+	}
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			if conn.logger != nil {
+				conn.logger.Error(context.ContextId(conn.contextId), fmt.Sprintf("WebSocket handler failed : %v\n%s", r, debug.Stack()))
+			}
+			_ = conn.Close(WebSocketCloseInternalServerError, "")
+		} else {
+			_ = conn.Close(WebSocketCloseNormalClosure, "")
+		}
+		conn.markClosed()
+	}()
+
+	handler(conn)
+}
+
+func (conn *WebSocketConnection) GetContextId() string {
+	return conn.contextId
+}
+
+func (conn *WebSocketConnection) GetPath() string {
+	return conn.path
+}
+
+func (conn *WebSocketConnection) GetSubprotocol() string {
+	return conn.subprotocol
+}
+
+func (conn *WebSocketConnection) GetPathVariable(name string) (string, bool) {
+	value, ok := conn.pathVariables[name]
+	return value, ok
+}
+
+func (conn *WebSocketConnection) GetRequestHeader(key string) (string, bool) {
+	value, ok := conn.headers[http.CanonicalHeaderKey(key)]
+	if !ok {
+		value, ok = conn.headers[key]
+	}
+	return value, ok
+}
+
+func (conn *WebSocketConnection) Get(key string) interface{} {
+	return conn.values[key]
+}
+
+func (conn *WebSocketConnection) RemoteAddr() net.Addr {
+	return conn.netConn.RemoteAddr()
+}
+
+func (conn *WebSocketConnection) Done() <-chan struct{} {
+	return conn.closed
+}
+
+func (conn *WebSocketConnection) ReadMessage() (int, []byte, error) {
+	messageType := -1
+	message := make([]byte, 0)
+
+	for {
+		fin, opcode, payload, err := conn.readFrame()
+		if err != nil {
+			conn.handleReadError(err)
+			return -1, nil, err
+		}
+
+		if conn.pingInterval > 0 {
+			conn.extendReadDeadline()
+		}
+
+		switch opcode {
+		case WebSocketPingMessage:
+			if err = conn.writeFrame(WebSocketPongMessage, payload); err != nil {
+				return -1, nil, err
+			}
+			continue
+		case WebSocketPongMessage:
+			continue
+		case WebSocketCloseMessage:
+			closeErr := parseWebSocketCloseFrame(payload)
+			code := closeErr.Code
+			if code == WebSocketCloseNoStatusReceived {
+				code = WebSocketCloseNormalClosure
+			}
+			_ = conn.Close(code, "")
+			return -1, nil, closeErr
+		case WebSocketTextMessage, WebSocketBinaryMessage:
+			if messageType != -1 {
+				conn.handleReadError(errWebSocketProtocol)
+				return -1, nil, errWebSocketProtocol
+			}
+			messageType = int(opcode)
+		case WebSocketContinuationMessage:
+			if messageType == -1 {
+				conn.handleReadError(errWebSocketProtocol)
+				return -1, nil, errWebSocketProtocol
+			}
+		default:
+			conn.handleReadError(errWebSocketProtocol)
+			return -1, nil, errWebSocketProtocol
+		}
+
+		if int64(len(message)+len(payload)) > conn.readLimit {
+			conn.handleReadError(ErrWebSocketReadLimit)
+			return -1, nil, ErrWebSocketReadLimit
+		}
+		message = append(message, payload...)
+
+		if fin {
+			break
+		}
+	}
+
+	if messageType == WebSocketTextMessage && !utf8.Valid(message) {
+		conn.handleReadError(errWebSocketInvalidFrame)
+		return -1, nil, errWebSocketInvalidFrame
+	}
+	return messageType, message, nil
+}
+
+func (conn *WebSocketConnection) ReadJSON(value interface{}) error {
+	_, message, err := conn.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(message, value)
+}
+
+func (conn *WebSocketConnection) WriteMessage(messageType int, data []byte) error {
+	if messageType != WebSocketTextMessage && messageType != WebSocketBinaryMessage {
+		return errors.New("websocket: message type must be text or binary")
+	}
+	return conn.writeFrame(byte(messageType), data)
+}
+
+func (conn *WebSocketConnection) WriteText(text string) error {
+	return conn.writeFrame(WebSocketTextMessage, []byte(text))
+}
+
+func (conn *WebSocketConnection) WriteJSON(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return conn.writeFrame(WebSocketTextMessage, data)
+}
+
+func (conn *WebSocketConnection) Ping(data []byte) error {
+	if len(data) > webSocketMaxControlPayload {
+		return errors.New("websocket: control frame payload is too large")
+	}
+	return conn.writeFrame(WebSocketPingMessage, data)
+}
+
+func (conn *WebSocketConnection) Close(code int, text string) error {
+	payload := make([]byte, 2, 2+len(text))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, text...)
+	if len(payload) > webSocketMaxControlPayload {
+		payload = payload[:webSocketMaxControlPayload]
+	}
+
+	err := conn.writeFrame(WebSocketCloseMessage, payload)
+	conn.markClosed()
+	if err == ErrWebSocketClosed {
+		return nil
+	}
+	return err
+}
+
+func (conn *WebSocketConnection) markClosed() {
+	conn.closeOnce.Do(func() {
+		close(conn.closed)
+	})
+}
+
+func (conn *WebSocketConnection) handleReadError(err error) {
+	switch err {
+	case errWebSocketProtocol:
+		_ = conn.Close(WebSocketCloseProtocolError, "")
+	case errWebSocketInvalidFrame:
+		_ = conn.Close(WebSocketCloseInvalidFramePayloadData, "")
+	case ErrWebSocketReadLimit:
+		_ = conn.Close(WebSocketCloseMessageTooBig, "")
+	default:
+		conn.markClosed()
+	}
+}
+
+func (conn *WebSocketConnection) extendReadDeadline() {
+	_ = conn.netConn.SetReadDeadline(time.Now().Add(2 * conn.pingInterval))
+}
+
+func (conn *WebSocketConnection) keepAlive() {
+	ticker := time.NewTicker(conn.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if conn.Ping(nil) != nil {
+				return
+			}
+		case <-conn.closed:
+			return
+		}
+	}
+}
+
+func (conn *WebSocketConnection) readFrame() (bool, byte, []byte, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(conn.reader, header[:2]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := int64(header[1] & 0x7F)
+
+	if header[0]&0x70 != 0 || !masked {
+		return false, 0, nil, errWebSocketProtocol
+	}
+
+	switch length {
+	case 126:
+		if _, err := io.ReadFull(conn.reader, header[:2]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(header[:2]))
+	case 127:
+		if _, err := io.ReadFull(conn.reader, header[:8]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(header[:8]))
+		if length < 0 {
+			return false, 0, nil, errWebSocketProtocol
+		}
+	}
+
+	if opcode >= WebSocketCloseMessage && (!fin || length > webSocketMaxControlPayload) {
+		return false, 0, nil, errWebSocketProtocol
+	}
+
+	if length > conn.readLimit {
+		return false, 0, nil, ErrWebSocketReadLimit
+	}
+
+	var maskKey [4]byte
+	if _, err := io.ReadFull(conn.reader, maskKey[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(conn.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	for index := range payload {
+		payload[index] ^= maskKey[index%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+func (conn *WebSocketConnection) writeFrame(opcode byte, payload []byte) error {
+	conn.writeMu.Lock()
+	defer conn.writeMu.Unlock()
+
+	if conn.closeSent {
+		return ErrWebSocketClosed
+	}
+
+	frame := make([]byte, 0, len(payload)+10)
+	frame = append(frame, 0x80|opcode)
+
+	length := len(payload)
+	switch {
+	case length <= 125:
+		frame = append(frame, byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, 126, byte(length>>8), byte(length))
+	default:
+		frame = append(frame, 127)
+		var extendedLength [8]byte
+		binary.BigEndian.PutUint64(extendedLength[:], uint64(length))
+		frame = append(frame, extendedLength[:]...)
+	}
+	frame = append(frame, payload...)
+
+	if conn.writeTimeout > 0 {
+		_ = conn.netConn.SetWriteDeadline(time.Now().Add(conn.writeTimeout))
+	}
+
+	if opcode == WebSocketCloseMessage {
+		conn.closeSent = true
+	}
+
+	_, err := conn.netConn.Write(frame)
+	return err
+}
+
+func parseWebSocketCloseFrame(payload []byte) *WebSocketCloseError {
+	if len(payload) < 2 {
+		return &WebSocketCloseError{Code: WebSocketCloseNoStatusReceived}
+	}
+	return &WebSocketCloseError{
+		Code: int(binary.BigEndian.Uint16(payload[:2])),
+		Text: string(payload[2:]),
+	}
+}
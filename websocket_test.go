@@ -0,0 +1,241 @@
+package web
+
+import (
+	"bufio"
+	"encoding/binary"
+	"github.com/procyon-projects/procyon-context"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+type testWebSocketAuthInterceptor struct {
+}
+
+func (interceptor testWebSocketAuthInterceptor) HandleBefore(requestContext *WebRequestContext) {
+	token, ok := requestContext.GetRequestHeader("X-Token")
+	if !ok {
+		requestContext.SetHTTPError(HttpErrorUnauthorized)
+		requestContext.Cancel()
+		return
+	}
+
+	if _, ok := requestContext.GetRequestHeader("X-Reject"); ok {
+		requestContext.SetHTTPError(HttpErrorForbidden)
+		return
+	}
+	requestContext.Put("token", token)
+}
+
+type testWebSocketMessage struct {
+	Text  string `json:"text"`
+	Token string `json:"token"`
+}
+
+func newTestWebSocketServer(t *testing.T, handler RequestHandler) *fasthttputil.InmemoryListener {
+	interceptorRegistry := NewSimpleHandlerInterceptorRegistry()
+	interceptorRegistry.RegisterHandlerInterceptor(testWebSocketAuthInterceptor{})
+
+	router := &ProcyonRouter{
+		recoveryActive:      true,
+		errorHandlerManager: newErrorHandlerManager(context.NewSimpleLogger()),
+		responseBodyWriter:  newDefaultResponseBodyWriter(),
+		handlerMapping:      NewRequestHandlerMapping(NewRequestMappingRegistry(), interceptorRegistry),
+	}
+	router.requestContextPool = &sync.Pool{
+		New: router.newWebRequestContext,
+	}
+	router.handlerMapping.RegisterHandlerMethod(handler.Path, handler.Method, handler.HandlerFunc, nil)
+
+	listener := fasthttputil.NewInmemoryListener()
+	go func() {
+		_ = fasthttp.Serve(listener, router.Route)
+	}()
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	return listener
+}
+
+func dialTestWebSocket(t *testing.T, listener *fasthttputil.InmemoryListener, headers map[string]string) (net.Conn, *bufio.Reader, *http.Response) {
+	conn, err := listener.Dial()
+	assert.Nil(t, err)
+
+	request := "GET /chat/general HTTP/1.1\r\nHost: localhost\r\n"
+	for key, value := range headers {
+		request += key + ": " + value + "\r\n"
+	}
+	_, err = conn.Write([]byte(request + "\r\n"))
+	assert.Nil(t, err)
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	assert.Nil(t, err)
+	return conn, reader, response
+}
+
+func writeTestWebSocketFrame(t *testing.T, conn net.Conn, opcode byte, payload []byte) {
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for index, value := range payload {
+		frame = append(frame, value^mask[index%4])
+	}
+	_, err := conn.Write(frame)
+	assert.Nil(t, err)
+}
+
+func readTestWebSocketFrame(t *testing.T, reader *bufio.Reader) (byte, []byte) {
+	var header [2]byte
+	_, err := io.ReadFull(reader, header[:])
+	assert.Nil(t, err)
+
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var extended [2]byte
+		_, err = io.ReadFull(reader, extended[:])
+		assert.Nil(t, err)
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(reader, payload)
+	assert.Nil(t, err)
+	return header[0] & 0x0F, payload
+}
+
+func webSocketUpgradeHeaders() map[string]string {
+	return map[string]string{
+		"Upgrade":               "websocket",
+		"Connection":            "Upgrade",
+		"Sec-WebSocket-Key":     "dGhlIHNhbXBsZSBub25jZQ==",
+		"Sec-WebSocket-Version": "13",
+		"X-Token":               "secret",
+	}
+}
+
+func TestWebSocket_Upgrade(t *testing.T) {
+	handler := WebSocket(func(conn *WebSocketConnection) {
+		room, _ := conn.GetPathVariable("room")
+		for {
+			message := &testWebSocketMessage{}
+			if err := conn.ReadJSON(message); err != nil {
+				return
+			}
+			message.Text = room + ":" + message.Text
+			message.Token = conn.Get("token").(string)
+			_ = conn.WriteJSON(message)
+		}
+	}, Path("/chat/:room"), WebSocketOrigins("https://procyon.dev"), WebSocketSubprotocols("chat"))
+
+	listener := newTestWebSocketServer(t, handler)
+
+	headers := webSocketUpgradeHeaders()
+	headers["Origin"] = "https://procyon.dev"
+	headers["Sec-WebSocket-Protocol"] = "v2, chat"
+	conn, reader, response := dialTestWebSocket(t, listener, headers)
+	defer conn.Close()
+
+	assert.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", response.Header.Get("Sec-WebSocket-Accept"))
+	assert.Equal(t, "chat", response.Header.Get("Sec-WebSocket-Protocol"))
+
+	writeTestWebSocketFrame(t, conn, WebSocketPingMessage, []byte("ping"))
+	opcode, payload := readTestWebSocketFrame(t, reader)
+	assert.Equal(t, byte(WebSocketPongMessage), opcode)
+	assert.Equal(t, "ping", string(payload))
+
+	writeTestWebSocketFrame(t, conn, WebSocketTextMessage, []byte("{\"text\":\"hello\"}"))
+	opcode, payload = readTestWebSocketFrame(t, reader)
+	assert.Equal(t, byte(WebSocketTextMessage), opcode)
+	assert.Equal(t, "{\"text\":\"general:hello\",\"token\":\"secret\"}", string(payload))
+
+	writeTestWebSocketFrame(t, conn, WebSocketCloseMessage, []byte{0x03, 0xE8})
+	opcode, payload = readTestWebSocketFrame(t, reader)
+	assert.Equal(t, byte(WebSocketCloseMessage), opcode)
+	assert.Equal(t, WebSocketCloseNormalClosure, int(binary.BigEndian.Uint16(payload)))
+}
+
+func TestWebSocket_RejectedByInterceptor(t *testing.T) {
+	listener := newTestWebSocketServer(t, WebSocket(func(conn *WebSocketConnection) {
+		t.Error("handler must not be invoked")
+	}, Path("/chat/:room")))
+
+	headers := webSocketUpgradeHeaders()
+	delete(headers, "X-Token")
+	conn, _, response := dialTestWebSocket(t, listener, headers)
+	defer conn.Close()
+
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestWebSocket_RejectedByInterceptorWithoutCancel(t *testing.T) {
+	listener := newTestWebSocketServer(t, WebSocket(func(conn *WebSocketConnection) {
+		t.Error("handler must not be invoked")
+	}, Path("/chat/:room")))
+
+	headers := webSocketUpgradeHeaders()
+	headers["X-Reject"] = "true"
+	conn, _, response := dialTestWebSocket(t, listener, headers)
+	defer conn.Close()
+
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestWebSocket_RejectedByOriginCheck(t *testing.T) {
+	listener := newTestWebSocketServer(t, WebSocket(func(conn *WebSocketConnection) {
+		t.Error("handler must not be invoked")
+	}, Path("/chat/:room")))
+
+	headers := webSocketUpgradeHeaders()
+	headers["Origin"] = "https://evil.example"
+	conn, _, response := dialTestWebSocket(t, listener, headers)
+	defer conn.Close()
+
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestWebSocket_RejectedWithoutUpgradeHeaders(t *testing.T) {
+	listener := newTestWebSocketServer(t, WebSocket(func(conn *WebSocketConnection) {
+		t.Error("handler must not be invoked")
+	}, Path("/chat/:room")))
+
+	conn, _, response := dialTestWebSocket(t, listener, map[string]string{"X-Token": "secret"})
+	defer conn.Close()
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestWebSocket_ClosesOnFrameLargerThanReadLimit(t *testing.T) {
+	readErr := make(chan error, 1)
+	listener := newTestWebSocketServer(t, WebSocket(func(conn *WebSocketConnection) {
+		_, _, err := conn.ReadMessage()
+		readErr <- err
+	}, Path("/chat/:room")))
+
+	conn, reader, response := dialTestWebSocket(t, listener, webSocketUpgradeHeaders())
+	defer conn.Close()
+	assert.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+
+	frame := []byte{0x80 | WebSocketBinaryMessage, 0x80 | 127}
+	frame = append(frame, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(frame[2:], uint64(DefaultWebSocketReadLimit+1))
+	_, err := conn.Write(frame)
+	assert.Nil(t, err)
+
+	opcode, payload := readTestWebSocketFrame(t, reader)
+	assert.Equal(t, byte(WebSocketCloseMessage), opcode)
+	assert.Equal(t, WebSocketCloseMessageTooBig, int(binary.BigEndian.Uint16(payload)))
+	assert.Equal(t, ErrWebSocketReadLimit, <-readErr)
+}
+
+func TestWebSocketReadLimit_MustBePositive(t *testing.T) {
+	assert.Panics(t, func() {
+		WebSocketReadLimit(0)
+	})
+}
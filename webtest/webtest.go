@@ -0,0 +1,232 @@
+package webtest
+
+import (
+	json "github.com/json-iterator/go"
+	web "github.com/procyon-projects/procyon-web"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"net"
+	"testing"
+)
+
+const testHost = "webtest.local"
+
+type Server struct {
+	t        testing.TB
+	router   *web.ProcyonRouter
+	listener *fasthttputil.InmemoryListener
+	server   *fasthttp.Server
+	client   *fasthttp.Client
+}
+
+func NewServer(t testing.TB, options ...web.RouterOption) *Server {
+	return NewServerWithRouter(t, web.NewRouter(options...))
+}
+
+func NewServerWithRouter(t testing.TB, router *web.ProcyonRouter) *Server {
+	listener := fasthttputil.NewInmemoryListener()
+	server := &Server{
+		t:        t,
+		router:   router,
+		listener: listener,
+		server: &fasthttp.Server{
+			Handler: router.Route,
+		},
+		client: &fasthttp.Client{
+			Dial: func(addr string) (net.Conn, error) {
+				return listener.Dial()
+			},
+		},
+	}
+
+	go func() {
+		_ = server.server.Serve(listener)
+	}()
+
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (server *Server) GetRouter() *web.ProcyonRouter {
+	return server.router
+}
+
+func (server *Server) Dial() (net.Conn, error) {
+	return server.listener.Dial()
+}
+
+func (server *Server) Close() {
+	server.client.CloseIdleConnections()
+	_ = server.server.Shutdown()
+	_ = server.listener.Close()
+}
+
+func (server *Server) Request(method string, path string) *RequestBuilder {
+	request := fasthttp.AcquireRequest()
+	request.Header.SetMethod(method)
+	request.SetRequestURI("http://" + testHost + path)
+
+	return &RequestBuilder{
+		server:  server,
+		request: request,
+	}
+}
+
+func (server *Server) Get(path string) *RequestBuilder {
+	return server.Request(fasthttp.MethodGet, path)
+}
+
+func (server *Server) Post(path string) *RequestBuilder {
+	return server.Request(fasthttp.MethodPost, path)
+}
+
+func (server *Server) Put(path string) *RequestBuilder {
+	return server.Request(fasthttp.MethodPut, path)
+}
+
+func (server *Server) Patch(path string) *RequestBuilder {
+	return server.Request(fasthttp.MethodPatch, path)
+}
+
+func (server *Server) Delete(path string) *RequestBuilder {
+	return server.Request(fasthttp.MethodDelete, path)
+}
+
+func (server *Server) Head(path string) *RequestBuilder {
+	return server.Request(fasthttp.MethodHead, path)
+}
+
+func (server *Server) Options(path string) *RequestBuilder {
+	return server.Request(fasthttp.MethodOptions, path)
+}
+
+type RequestBuilder struct {
+	server  *Server
+	request *fasthttp.Request
+}
+
+func (builder *RequestBuilder) Header(key string, value string) *RequestBuilder {
+	builder.request.Header.Add(key, value)
+	return builder
+}
+
+func (builder *RequestBuilder) ContentType(contentType string) *RequestBuilder {
+	builder.request.Header.SetContentType(contentType)
+	return builder
+}
+
+func (builder *RequestBuilder) Query(key string, value string) *RequestBuilder {
+	builder.request.URI().QueryArgs().Add(key, value)
+	return builder
+}
+
+func (builder *RequestBuilder) Cookie(name string, value string) *RequestBuilder {
+	builder.request.Header.SetCookie(name, value)
+	return builder
+}
+
+func (builder *RequestBuilder) Body(body []byte) *RequestBuilder {
+	builder.request.SetBody(body)
+	return builder
+}
+
+func (builder *RequestBuilder) JSON(value interface{}) *RequestBuilder {
+	builder.server.t.Helper()
+
+	body, err := json.Marshal(value)
+	if err != nil {
+		builder.server.t.Fatalf("request body cannot be marshalled : %s", err.Error())
+	}
+
+	builder.request.SetBody(body)
+	builder.request.Header.SetContentType(web.MediaTypeApplicationJsonValue)
+	return builder
+}
+
+func (builder *RequestBuilder) Do() *Response {
+	builder.server.t.Helper()
+	defer fasthttp.ReleaseRequest(builder.request)
+
+	response := &fasthttp.Response{}
+	err := builder.server.client.Do(builder.request, response)
+	if err != nil {
+		builder.server.t.Fatalf("request cannot be sent : %s", err.Error())
+	}
+
+	return &Response{
+		t:        builder.server.t,
+		response: response,
+	}
+}
+
+func (builder *RequestBuilder) Expect(status int) *Response {
+	builder.server.t.Helper()
+	return builder.Do().Status(status)
+}
+
+type Response struct {
+	t        testing.TB
+	response *fasthttp.Response
+}
+
+func (response *Response) StatusCode() int {
+	return response.response.StatusCode()
+}
+
+func (response *Response) GetHeader(key string) string {
+	return string(response.response.Header.Peek(key))
+}
+
+func (response *Response) GetBody() []byte {
+	return response.response.Body()
+}
+
+func (response *Response) Status(status int) *Response {
+	response.t.Helper()
+	assert.Equal(response.t, status, response.response.StatusCode(), "unexpected status code")
+	return response
+}
+
+func (response *Response) Header(key string, value string) *Response {
+	response.t.Helper()
+	assert.Equal(response.t, value, response.GetHeader(key), "unexpected value for header "+key)
+	return response
+}
+
+func (response *Response) ContentType(contentType string) *Response {
+	response.t.Helper()
+	assert.Equal(response.t, contentType, string(response.response.Header.ContentType()), "unexpected content type")
+	return response
+}
+
+func (response *Response) Body(body string) *Response {
+	response.t.Helper()
+	assert.Equal(response.t, body, string(response.response.Body()), "unexpected body")
+	return response
+}
+
+func (response *Response) JSONBody(expected interface{}) *Response {
+	response.t.Helper()
+
+	expectedBody, ok := expected.(string)
+	if !ok {
+		encodedBody, err := json.Marshal(expected)
+		if err != nil {
+			response.t.Fatalf("expected body cannot be marshalled : %s", err.Error())
+		}
+		expectedBody = string(encodedBody)
+	}
+
+	assert.JSONEq(response.t, expectedBody, string(response.response.Body()))
+	return response
+}
+
+func (response *Response) DecodeJSON(target interface{}) *Response {
+	response.t.Helper()
+
+	if err := json.Unmarshal(response.response.Body(), target); err != nil {
+		response.t.Fatalf("response body cannot be unmarshalled : %s", err.Error())
+	}
+	return response
+}
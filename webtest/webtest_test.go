@@ -0,0 +1,117 @@
+package webtest
+
+import (
+	"errors"
+	web "github.com/procyon-projects/procyon-web"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type testUser struct {
+	Id   string `json:"id"`
+	Name string `json:"name" validate:"required"`
+}
+
+type testUserController struct {
+}
+
+func (controller testUserController) RegisterHandlers(registry web.HandlerRegistry) {
+	registry.RegisterGroup("/users",
+		web.Get(controller.getUser, web.Path("/:id")),
+		web.Post(controller.createUser, web.Path(""), web.RequestObject(testUser{}), web.Validate()),
+	)
+}
+
+func (controller testUserController) getUser(ctx *web.WebRequestContext) {
+	id, _ := ctx.GetPathVariable("id")
+	if id == "0" {
+		ctx.ThrowError(web.HttpErrorNotFound)
+	}
+
+	ctx.Ok().SetModel(testUser{Id: id, Name: ctx.Get("user").(string)}).SetResponseContentType(web.MediaTypeApplicationJson)
+}
+
+func (controller testUserController) createUser(ctx *web.WebRequestContext) {
+	user := &testUser{}
+	if err := ctx.BindRequest(user); err != nil {
+		ctx.ThrowError(err)
+	}
+
+	ctx.Created("/users/" + user.Id).SetModel(user).SetResponseContentType(web.MediaTypeApplicationJson)
+}
+
+type testUserInterceptor struct {
+}
+
+func (interceptor testUserInterceptor) HandleBefore(ctx *web.WebRequestContext) {
+	user, _ := ctx.GetRequestHeader("X-User")
+	ctx.Put("user", user)
+}
+
+type fakeValidator struct {
+	validated []interface{}
+}
+
+func (validator *fakeValidator) Validate(val interface{}) error {
+	validator.validated = append(validator.validated, val)
+	return errors.New("rejected by fake")
+}
+
+type fakeErrorHandler struct {
+}
+
+func (handler fakeErrorHandler) HandleError(err error, ctx *web.WebRequestContext) {
+	ctx.SetResponseStatus(http.StatusTeapot)
+	ctx.SetModel(err.Error())
+	ctx.SetResponseContentType(web.MediaTypeApplicationTextHtml)
+}
+
+func TestServer_Get(t *testing.T) {
+	server := NewServer(t, web.WithControllers(testUserController{}), web.WithInterceptors(testUserInterceptor{}))
+
+	server.Get("/users/1").
+		Header("X-User", "procyon").
+		Expect(http.StatusOK).
+		ContentType(web.MediaTypeApplicationJsonValue).
+		JSONBody(testUser{Id: "1", Name: "procyon"})
+
+	server.Get("/users/0").
+		Expect(http.StatusNotFound).
+		JSONBody("{\"Code\":404,\"Message\":\"Not Found\"}")
+
+	server.Get("/accounts").Expect(http.StatusNotFound)
+}
+
+func TestServer_PostWithValidation(t *testing.T) {
+	server := NewServer(t, web.WithControllers(testUserController{}))
+
+	user := &testUser{}
+	server.Post("/users").
+		JSON(testUser{Id: "2", Name: "web"}).
+		Expect(http.StatusCreated).
+		Header("Location", "/users/2").
+		DecodeJSON(user)
+	assert.Equal(t, "web", user.Name)
+
+	server.Post("/users").
+		JSON(testUser{Id: "3"}).
+		Expect(http.StatusBadRequest).
+		JSONBody("{\"Code\":400,\"Message\":\"Validation failed\",\"Errors\":[{\"field\":\"name\",\"rule\":\"required\",\"message\":\"name is a required field\"}]}")
+}
+
+func TestServer_WithFakes(t *testing.T) {
+	validator := &fakeValidator{}
+	server := NewServer(t,
+		web.WithControllers(testUserController{}),
+		web.WithValidator(validator),
+		web.WithErrorHandler(fakeErrorHandler{}),
+	)
+
+	server.Post("/users").
+		JSON(testUser{Id: "4", Name: "fake"}).
+		Expect(http.StatusTeapot).
+		Body("rejected by fake")
+
+	assert.Len(t, validator.validated, 1)
+}